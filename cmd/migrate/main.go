@@ -0,0 +1,24 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"github.com/Closi-App/backend/internal/migration"
+	"github.com/Closi-App/backend/pkg/config"
+	"github.com/Closi-App/backend/pkg/database/mongo"
+	"github.com/Closi-App/backend/pkg/logger"
+)
+
+func main() {
+	cfgFilePath := flag.String("config", "./config.yml", "config file path, eg: -config ./configs/local.yml")
+	name := flag.String("name", "", "migration name, eg: -name normalize_tags")
+	flag.Parse()
+	cfg := config.NewConfig(*cfgFilePath)
+
+	log := logger.NewLogger(cfg)
+	db := mongo.NewMongo(cfg)
+
+	if err := migration.Run(context.Background(), db, log, *name); err != nil {
+		log.Fatal().Err(err).Msg("migration failed")
+	}
+}
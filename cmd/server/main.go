@@ -6,6 +6,9 @@ import (
 	"github.com/Closi-App/backend/cmd/server/wire"
 	"github.com/Closi-App/backend/pkg/config"
 	"golang.org/x/text/language"
+
+	// users time zones must be known even if the system has no time zone database
+	_ "time/tzdata"
 )
 
 //	@title			Closi API
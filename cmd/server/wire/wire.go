@@ -7,6 +7,7 @@ import (
 	"github.com/Closi-App/backend/internal/app"
 	"github.com/Closi-App/backend/internal/delivery/http"
 	"github.com/Closi-App/backend/internal/delivery/http/v1"
+	"github.com/Closi-App/backend/internal/delivery/worker"
 	"github.com/Closi-App/backend/internal/repository"
 	"github.com/Closi-App/backend/internal/service"
 	"github.com/Closi-App/backend/pkg/auth"
@@ -27,9 +28,10 @@ var pkgSet = wire.NewSet(
 	mongo.NewMongo,
 	redis.NewRedis,
 	imgbb.NewImgbb,
-	smtp.NewSMTPSender,
+	smtp.NewSender,
 	auth.NewTokensManager,
 	auth.NewPasswordHasher,
+	auth.NewSigner,
 )
 
 var repositorySet = wire.NewSet(
@@ -39,7 +41,18 @@ var repositorySet = wire.NewSet(
 	repository.NewTagRepository,
 	repository.NewUserRepository,
 	repository.NewQuestionRepository,
+	repository.NewQuestionVoteRepository,
+	repository.NewQuestionViewRepository,
 	repository.NewAnswerRepository,
+	repository.NewAnswerLikeRepository,
+	repository.NewCommentRepository,
+	repository.NewRevisionRepository,
+	repository.NewQuestionDraftRepository,
+	repository.NewFavoriteRepository,
+	repository.NewFollowRepository,
+	repository.NewNotificationRepository,
+	repository.NewRealtimeRepository,
+	repository.NewEmailOutboxRepository,
 )
 
 var serviceSet = wire.NewSet(
@@ -47,19 +60,33 @@ var serviceSet = wire.NewSet(
 	service.NewCountryService,
 	service.NewImageService,
 	service.NewEmailService,
+	service.NewEventService,
 	service.NewTagService,
+	service.NewRevisionService,
 	service.NewUserService,
 	service.NewQuestionService,
 	service.NewAnswerService,
+	service.NewModerationService,
+	service.NewCommentService,
+	service.NewPurgeService,
+	service.NewQuestionDraftService,
+	service.NewBountyService,
+	service.NewDigestService,
+	service.NewFavoriteService,
+	service.NewFollowService,
+	service.NewFeedService,
+	service.NewNotificationService,
+	service.NewRealtimeService,
 )
 
 var deliverySet = wire.NewSet(
 	v1.NewHandler,
 	http.NewServer,
+	worker.NewWorker,
 )
 
-func newApp(cfg *viper.Viper, log *logger.Logger, httpServer *http.Server) *app.App {
-	return app.NewApp(cfg, log, httpServer)
+func newApp(cfg *viper.Viper, log *logger.Logger, httpServer *http.Server, worker *worker.Worker) *app.App {
+	return app.NewApp(cfg, log, httpServer, worker)
 }
 
 func NewWire(*viper.Viper, []language.Tag) (*app.App, func(), error) {
@@ -10,6 +10,7 @@ import (
 	"github.com/Closi-App/backend/internal/app"
 	"github.com/Closi-App/backend/internal/delivery/http"
 	"github.com/Closi-App/backend/internal/delivery/http/v1"
+	"github.com/Closi-App/backend/internal/delivery/worker"
 	"github.com/Closi-App/backend/internal/repository"
 	"github.com/Closi-App/backend/internal/service"
 	"github.com/Closi-App/backend/pkg/auth"
@@ -38,35 +39,61 @@ func NewWire(viperViper *viper.Viper, arg []language.Tag) (*app.App, func(), err
 	countryService := service.NewCountryService(serviceService, countryRepository)
 	imageRepository := repository.NewImageRepository(repositoryRepository)
 	imageService := service.NewImageService(serviceService, imageRepository)
-	tagRepository := repository.NewTagRepository(repositoryRepository)
-	tagService := service.NewTagService(serviceService, tagRepository)
+	emailOutboxRepository := repository.NewEmailOutboxRepository(repositoryRepository)
 	userRepository := repository.NewUserRepository(repositoryRepository)
-	sender := smtp.NewSMTPSender(viperViper)
-	emailService := service.NewEmailService(serviceService, localizerLocalizer, sender)
+	eventService := service.NewEventService(serviceService)
+	sender := smtp.NewSender(viperViper, loggerLogger)
+	signer := auth.NewSigner(viperViper)
+	emailService := service.NewEmailService(serviceService, viperViper, emailOutboxRepository, userRepository, eventService, localizerLocalizer, sender, signer, arg)
+	tagRepository := repository.NewTagRepository(repositoryRepository)
+	questionRepository := repository.NewQuestionRepository(repositoryRepository)
+	revisionRepository := repository.NewRevisionRepository(repositoryRepository)
+	followRepository := repository.NewFollowRepository(repositoryRepository)
+	tagService := service.NewTagService(serviceService, viperViper, tagRepository, questionRepository, revisionRepository, followRepository)
+	favoriteRepository := repository.NewFavoriteRepository(repositoryRepository)
 	passwordHasher := auth.NewPasswordHasher(viperViper)
 	tokensManager := auth.NewTokensManager(viperViper)
-	userService := service.NewUserService(serviceService, viperViper, userRepository, emailService, passwordHasher, tokensManager)
-	questionRepository := repository.NewQuestionRepository(repositoryRepository)
-	questionService := service.NewQuestionService(serviceService, questionRepository, tagService)
+	userService := service.NewUserService(serviceService, viperViper, userRepository, followRepository, favoriteRepository, questionRepository, emailService, eventService, passwordHasher, tokensManager)
+	questionVoteRepository := repository.NewQuestionVoteRepository(repositoryRepository)
+	questionViewRepository := repository.NewQuestionViewRepository(repositoryRepository)
+	revisionService := service.NewRevisionService(serviceService, revisionRepository)
+	questionService := service.NewQuestionService(serviceService, viperViper, questionRepository, questionVoteRepository, questionViewRepository, tagService, userService, revisionService)
 	answerRepository := repository.NewAnswerRepository(repositoryRepository)
-	answerService := service.NewAnswerService(serviceService, answerRepository, questionService, userService)
-	handler := v1.NewHandler(loggerLogger, localizerLocalizer, countryService, imageService, tagService, userService, questionService, answerService, tokensManager, arg)
+	answerLikeRepository := repository.NewAnswerLikeRepository(repositoryRepository)
+	answerService := service.NewAnswerService(serviceService, viperViper, answerRepository, answerLikeRepository, questionService, userService, revisionService, eventService)
+	commentRepository := repository.NewCommentRepository(repositoryRepository)
+	moderationService := service.NewModerationService(serviceService, viperViper)
+	commentService := service.NewCommentService(serviceService, commentRepository, moderationService, questionService, answerService, userService)
+	questionDraftRepository := repository.NewQuestionDraftRepository(repositoryRepository)
+	questionDraftService := service.NewQuestionDraftService(serviceService, questionDraftRepository, questionService)
+	favoriteService := service.NewFavoriteService(serviceService, favoriteRepository, questionRepository)
+	followService := service.NewFollowService(serviceService, followRepository, userService, tagService, questionService)
+	feedService := service.NewFeedService(serviceService, followRepository, questionRepository, answerRepository)
+	notificationRepository := repository.NewNotificationRepository(repositoryRepository)
+	notificationService := service.NewNotificationService(serviceService, localizerLocalizer, notificationRepository, questionRepository, userService, emailService, eventService)
+	realtimeRepository := repository.NewRealtimeRepository(repositoryRepository)
+	realtimeService := service.NewRealtimeService(serviceService, realtimeRepository, eventService)
+	handler := v1.NewHandler(viperViper, loggerLogger, localizerLocalizer, countryService, imageService, emailService, tagService, userService, questionService, answerService, commentService, questionDraftService, favoriteService, followService, feedService, notificationService, realtimeService, tokensManager, sender, arg)
 	server := http.NewServer(viperViper, loggerLogger, handler)
-	appApp := newApp(viperViper, loggerLogger, server)
+	purgeService := service.NewPurgeService(serviceService, viperViper, questionService, answerService, commentService, favoriteService, followService)
+	bountyService := service.NewBountyService(serviceService, questionRepository, answerRepository, userService, emailService)
+	digestService := service.NewDigestService(serviceService, userRepository, questionRepository, followRepository, notificationRepository, emailService)
+	workerWorker := worker.NewWorker(viperViper, loggerLogger, purgeService, questionService, questionDraftService, bountyService, digestService, emailService, realtimeService)
+	appApp := newApp(viperViper, loggerLogger, server, workerWorker)
 	return appApp, func() {
 	}, nil
 }
 
 // wire.go:
 
-var pkgSet = wire.NewSet(localizer.NewLocalizer, logger.NewLogger, mongo.NewMongo, redis.NewRedis, imgbb.NewImgbb, smtp.NewSMTPSender, auth.NewTokensManager, auth.NewPasswordHasher)
+var pkgSet = wire.NewSet(localizer.NewLocalizer, logger.NewLogger, mongo.NewMongo, redis.NewRedis, imgbb.NewImgbb, smtp.NewSender, auth.NewTokensManager, auth.NewPasswordHasher, auth.NewSigner)
 
-var repositorySet = wire.NewSet(repository.NewRepository, repository.NewCountryRepository, repository.NewImageRepository, repository.NewTagRepository, repository.NewUserRepository, repository.NewQuestionRepository, repository.NewAnswerRepository)
+var repositorySet = wire.NewSet(repository.NewRepository, repository.NewCountryRepository, repository.NewImageRepository, repository.NewTagRepository, repository.NewUserRepository, repository.NewQuestionRepository, repository.NewQuestionVoteRepository, repository.NewQuestionViewRepository, repository.NewAnswerRepository, repository.NewAnswerLikeRepository, repository.NewCommentRepository, repository.NewRevisionRepository, repository.NewQuestionDraftRepository, repository.NewFavoriteRepository, repository.NewFollowRepository, repository.NewNotificationRepository, repository.NewRealtimeRepository, repository.NewEmailOutboxRepository)
 
-var serviceSet = wire.NewSet(service.NewService, service.NewCountryService, service.NewImageService, service.NewEmailService, service.NewTagService, service.NewUserService, service.NewQuestionService, service.NewAnswerService)
+var serviceSet = wire.NewSet(service.NewService, service.NewCountryService, service.NewImageService, service.NewEmailService, service.NewEventService, service.NewTagService, service.NewRevisionService, service.NewUserService, service.NewQuestionService, service.NewAnswerService, service.NewModerationService, service.NewCommentService, service.NewPurgeService, service.NewQuestionDraftService, service.NewBountyService, service.NewDigestService, service.NewFavoriteService, service.NewFollowService, service.NewFeedService, service.NewNotificationService, service.NewRealtimeService)
 
-var deliverySet = wire.NewSet(v1.NewHandler, http.NewServer)
+var deliverySet = wire.NewSet(v1.NewHandler, http.NewServer, worker.NewWorker)
 
-func newApp(cfg *viper.Viper, log *logger.Logger, httpServer *http.Server) *app.App {
-	return app.NewApp(cfg, log, httpServer)
+func newApp(cfg *viper.Viper, log *logger.Logger, httpServer *http.Server, worker2 *worker.Worker) *app.App {
+	return app.NewApp(cfg, log, httpServer, worker2)
 }
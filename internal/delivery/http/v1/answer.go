@@ -13,15 +13,23 @@ func (h *Handler) initAnswerRoutes(router fiber.Router) {
 	{
 		answers.Get("/", h.answerGetAllWithFilter)
 		answers.Get("/:id", h.answerGetByID)
+		answers.Get("/:id/revisions", h.answerGetRevisions)
 
 		auth := answers.Group("", h.userAuthMiddleware)
 		{
 			auth.Post("/", h.answerCreate)
 			auth.Put("/:id", h.answerUpdate)
 			auth.Delete("/:id", h.answerDelete)
+			auth.Post("/:id/restore", h.answerRestore)
+			auth.Post("/:id/verify", h.answerVerify)
 
 			auth.Put("/:id/likes", h.answerAddLike)
 			auth.Delete("/:id/likes", h.answerRemoveLike)
+
+			moderator := auth.Group("", h.moderatorMiddleware)
+			{
+				moderator.Post("/:id/revisions/:revisionID/rollback", h.answerRollback)
+			}
 		}
 	}
 }
@@ -39,7 +47,7 @@ type answerCreateRequest struct {
 // @Produce		json
 // @Param			answerCreateRequest	body		answerCreateRequest	true	"Request"
 // @Success		201					{object}	successResponse
-// @Failure		400,401,500			{object}	errorResponse
+// @Failure		400,401,404,500		{object}	errorResponse
 // @Router			/answers [post]
 func (h *Handler) answerCreate(ctx *fiber.Ctx) error {
 	var req answerCreateRequest
@@ -63,6 +71,10 @@ func (h *Handler) answerCreate(ctx *fiber.Ctx) error {
 		UserID:     ctxUser.ID,
 	})
 	if err != nil {
+		if errors.Is(err, domain.ErrQuestionNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+
 		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
 	}
 
@@ -151,7 +163,7 @@ type answerUpdateRequest struct {
 // @Param			id					path		string				true	"Answer ID"
 // @Param			answerUpdateRequest	body		answerUpdateRequest	true	"Request"
 // @Success		200					{object}	response
-// @Failure		400,401,500			{object}	errorResponse
+// @Failure		400,401,403,404,500	{object}	errorResponse
 // @Router			/answers/{id} [put]
 func (h *Handler) answerUpdate(ctx *fiber.Ctx) error {
 	var err error
@@ -175,6 +187,13 @@ func (h *Handler) answerUpdate(ctx *fiber.Ctx) error {
 	if err = h.answerService.Update(ctx.Context(), objectID, ctxUser.ID, domain.AnswerUpdateInput{
 		Text: req.Text,
 	}); err != nil {
+		if errors.Is(err, domain.ErrAnswerNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+		if errors.Is(err, domain.ErrForbidden) {
+			return h.newResponse(ctx, fiber.StatusForbidden, err)
+		}
+
 		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
 	}
 
@@ -187,9 +206,9 @@ func (h *Handler) answerUpdate(ctx *fiber.Ctx) error {
 // @Tags			answers
 // @Accept			json
 // @Produce		json
-// @Param			id			path		string	true	"Answer ID"
-// @Success		200			{object}	response
-// @Failure		400,401,500	{object}	errorResponse
+// @Param			id					path		string	true	"Answer ID"
+// @Success		200					{object}	response
+// @Failure		400,401,403,404,500	{object}	errorResponse
 // @Router			/answers/{id} [delete]
 func (h *Handler) answerDelete(ctx *fiber.Ctx) error {
 	id := ctx.Params("id")
@@ -204,6 +223,13 @@ func (h *Handler) answerDelete(ctx *fiber.Ctx) error {
 	}
 
 	if err = h.answerService.Delete(ctx.Context(), objectID, ctxUser.ID); err != nil {
+		if errors.Is(err, domain.ErrAnswerNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+		if errors.Is(err, domain.ErrForbidden) {
+			return h.newResponse(ctx, fiber.StatusForbidden, err)
+		}
+
 		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
 	}
 
@@ -216,9 +242,9 @@ func (h *Handler) answerDelete(ctx *fiber.Ctx) error {
 // @Tags			answers
 // @Accept			json
 // @Produce		json
-// @Param			id			path		string	true	"Answer ID"
-// @Success		200			{object}	response
-// @Failure		400,401,500	{object}	errorResponse
+// @Param			id				path		string	true	"Answer ID"
+// @Success		200				{object}	response
+// @Failure		400,401,404,500	{object}	errorResponse
 // @Router			/answers/{id}/likes [put]
 func (h *Handler) answerAddLike(ctx *fiber.Ctx) error {
 	id := ctx.Params("id")
@@ -227,7 +253,19 @@ func (h *Handler) answerAddLike(ctx *fiber.Ctx) error {
 		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
 	}
 
-	if err = h.answerService.AddLike(ctx.Context(), objectID); err != nil {
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	if err = h.answerService.AddLike(ctx.Context(), objectID, ctxUser.ID); err != nil {
+		if errors.Is(err, domain.ErrAnswerNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+		if errors.Is(err, domain.ErrAnswerOwnLike) {
+			return h.newResponse(ctx, fiber.StatusBadRequest, err)
+		}
+
 		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
 	}
 
@@ -240,9 +278,9 @@ func (h *Handler) answerAddLike(ctx *fiber.Ctx) error {
 // @Tags			answers
 // @Accept			json
 // @Produce		json
-// @Param			id			path		string	true	"Answer ID"
-// @Success		200			{object}	response
-// @Failure		400,401,500	{object}	errorResponse
+// @Param			id				path		string	true	"Answer ID"
+// @Success		200				{object}	response
+// @Failure		400,401,404,500	{object}	errorResponse
 // @Router			/answers/{id}/likes [delete]
 func (h *Handler) answerRemoveLike(ctx *fiber.Ctx) error {
 	id := ctx.Params("id")
@@ -251,7 +289,162 @@ func (h *Handler) answerRemoveLike(ctx *fiber.Ctx) error {
 		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
 	}
 
-	if err = h.answerService.RemoveLike(ctx.Context(), objectID); err != nil {
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	if err = h.answerService.RemoveLike(ctx.Context(), objectID, ctxUser.ID); err != nil {
+		if errors.Is(err, domain.ErrAnswerNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
+
+// @Summary		Get revisions
+// @Description	Get answer edit history with diffs between revisions
+// @Tags			answers
+// @Accept			json
+// @Produce		json
+// @Param			id			path		string	true	"Answer ID"
+// @Success		200			{object}	successResponse
+// @Failure		400,404,500	{object}	errorResponse
+// @Router			/answers/{id}/revisions [get]
+func (h *Handler) answerGetRevisions(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	revisions, err := h.answerService.GetRevisions(ctx.Context(), objectID)
+	if err != nil {
+		if errors.Is(err, domain.ErrAnswerNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK, revisions)
+}
+
+// @Summary		Rollback
+// @Description	Rollback answer to the revision (moderators only)
+// @Security		UserAuth
+// @Tags			answers
+// @Accept			json
+// @Produce		json
+// @Param			id					path		string	true	"Answer ID"
+// @Param			revisionID			path		string	true	"Revision ID"
+// @Success		200					{object}	response
+// @Failure		400,401,403,404,500	{object}	errorResponse
+// @Router			/answers/{id}/revisions/{revisionID}/rollback [post]
+func (h *Handler) answerRollback(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	revisionID := ctx.Params("revisionID")
+	revisionObjectID, err := bson.ObjectIDFromHex(revisionID)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	if err = h.answerService.Rollback(ctx.Context(), objectID, revisionObjectID, ctxUser.ID); err != nil {
+		if errors.Is(err, domain.ErrAnswerNotFound) || errors.Is(err, domain.ErrRevisionNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
+
+// @Summary		Verify
+// @Description	Verify answer and pay out the question points to its author (question author only)
+// @Security		UserAuth
+// @Tags			answers
+// @Accept			json
+// @Produce		json
+// @Param			id						path		string	true	"Answer ID"
+// @Success		200						{object}	response
+// @Failure		400,401,403,404,409,500	{object}	errorResponse
+// @Router			/answers/{id}/verify [post]
+func (h *Handler) answerVerify(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	if err = h.answerService.Verify(ctx.Context(), objectID, ctxUser.ID); err != nil {
+		if errors.Is(err, domain.ErrAnswerNotFound) || errors.Is(err, domain.ErrQuestionNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+		if errors.Is(err, domain.ErrForbidden) {
+			return h.newResponse(ctx, fiber.StatusForbidden, err)
+		}
+		if errors.Is(err, domain.ErrAnswerAlreadyVerified) {
+			return h.newResponse(ctx, fiber.StatusConflict, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
+
+// @Summary		Restore
+// @Description	Restore deleted answer within the restore window (owner or moderator)
+// @Security		UserAuth
+// @Tags			answers
+// @Accept			json
+// @Produce		json
+// @Param			id					path		string	true	"Answer ID"
+// @Success		200					{object}	response
+// @Failure		400,401,403,404,500	{object}	errorResponse
+// @Router			/answers/{id}/restore [post]
+func (h *Handler) answerRestore(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	if err = h.answerService.Restore(ctx.Context(), objectID, ctxUser.ID); err != nil {
+		if errors.Is(err, domain.ErrAnswerNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+		if errors.Is(err, domain.ErrForbidden) {
+			return h.newResponse(ctx, fiber.StatusForbidden, err)
+		}
+		if errors.Is(err, domain.ErrRestoreWindowExpired) {
+			return h.newResponse(ctx, fiber.StatusBadRequest, err)
+		}
+
 		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
 	}
 
@@ -0,0 +1,332 @@
+package v1
+
+import (
+	"errors"
+	"github.com/Closi-App/backend/internal/domain"
+	"github.com/Closi-App/backend/internal/service"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func (h *Handler) initCommentRoutes(router fiber.Router) {
+	comments := router.Group("/comments")
+	{
+		comments.Get("/", h.commentGetAllWithFilter)
+		comments.Get("/:id", h.commentGetByID)
+
+		auth := comments.Group("", h.userAuthMiddleware)
+		{
+			auth.Post("/", h.commentCreate)
+			auth.Put("/:id", h.commentUpdate)
+			auth.Delete("/:id", h.commentDelete)
+
+			moderator := auth.Group("", h.moderatorMiddleware)
+			{
+				moderator.Put("/:id/hidden", h.commentHide)
+				moderator.Delete("/:id/hidden", h.commentUnhide)
+			}
+		}
+	}
+}
+
+type commentCreateRequest struct {
+	Text       string  `json:"text"`
+	TargetType string  `json:"target_type"`
+	TargetID   string  `json:"target_id"`
+	ParentID   *string `json:"parent_id"`
+}
+
+// @Summary		Create
+// @Description	Create new comment for question or answer
+// @Security		UserAuth
+// @Tags			comments
+// @Accept			json
+// @Produce		json
+// @Param			commentCreateRequest	body		commentCreateRequest	true	"Request"
+// @Success		201						{object}	successResponse
+// @Failure		400,401,404,500			{object}	errorResponse
+// @Router			/comments [post]
+func (h *Handler) commentCreate(ctx *fiber.Ctx) error {
+	var req commentCreateRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	targetType, err := domain.ParseTargetType(req.TargetType)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, err)
+	}
+
+	targetObjectID, err := bson.ObjectIDFromHex(req.TargetID)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	var parentObjectID *bson.ObjectID
+
+	if req.ParentID != nil {
+		id, err := bson.ObjectIDFromHex(*req.ParentID)
+		if err != nil {
+			return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+		}
+
+		parentObjectID = &id
+	}
+
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	id, err := h.commentService.Create(ctx.Context(), service.CommentCreateInput{
+		Text:       req.Text,
+		TargetType: targetType,
+		TargetID:   targetObjectID,
+		ParentID:   parentObjectID,
+		UserID:     ctxUser.ID,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrQuestionNotFound) ||
+			errors.Is(err, domain.ErrAnswerNotFound) ||
+			errors.Is(err, domain.ErrCommentNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+		if errors.Is(err, domain.ErrBadRequest) ||
+			errors.Is(err, domain.ErrCommentInvalidParent) ||
+			errors.Is(err, domain.ErrCommentRejected) {
+			return h.newResponse(ctx, fiber.StatusBadRequest, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusCreated, idResponse{id.Hex()})
+}
+
+// @Summary		Get all with filter
+// @Description	Get all comments with filter
+// @Tags			comments
+// @Accept			json
+// @Produce		json
+// @Param			target_type	query		string	false	"Target type"	Enums(question, answer)
+// @Param			target_id	query		string	false	"Target ID"
+// @Param			user_id		query		string	false	"User ID"
+// @Success		200			{object}	successResponse
+// @Failure		400,500		{object}	errorResponse
+// @Router			/comments [get]
+func (h *Handler) commentGetAllWithFilter(ctx *fiber.Ctx) error {
+	targetType := ctx.Query("target_type")
+	targetID := ctx.Query("target_id")
+	userID := ctx.Query("user_id")
+
+	var filter domain.CommentGetAllFilter
+
+	if targetType != "" {
+		t, err := domain.ParseTargetType(targetType)
+		if err != nil {
+			return h.newResponse(ctx, fiber.StatusBadRequest, err)
+		}
+
+		filter.TargetType = &t
+	}
+	if targetID != "" {
+		id, err := bson.ObjectIDFromHex(targetID)
+		if err != nil {
+			return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+		}
+
+		filter.TargetID = &id
+	}
+	if userID != "" {
+		id, err := bson.ObjectIDFromHex(userID)
+		if err != nil {
+			return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+		}
+
+		filter.UserID = &id
+	}
+
+	comments, err := h.commentService.GetAll(ctx.Context(), filter)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK, comments)
+}
+
+// @Summary		Get by ID
+// @Description	Get comment by ID, hidden comments are available only to moderators
+// @Tags			comments
+// @Accept			json
+// @Produce		json
+// @Param			id			path		string	true	"Comment ID"
+// @Success		200			{object}	successResponse
+// @Failure		400,404,500	{object}	errorResponse
+// @Router			/comments/{id} [get]
+func (h *Handler) commentGetByID(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	comment, err := h.commentService.GetByID(ctx.Context(), objectID)
+	if err != nil {
+		if errors.Is(err, domain.ErrCommentNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	if comment.IsHidden && !h.isViewerModerator(ctx) {
+		return h.newResponse(ctx, fiber.StatusNotFound, domain.ErrCommentNotFound)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK, comment)
+}
+
+type commentUpdateRequest struct {
+	Text *string `json:"text"`
+}
+
+// @Summary		Update
+// @Description	Update comment
+// @Security		UserAuth
+// @Tags			comments
+// @Accept			json
+// @Produce		json
+// @Param			id						path		string					true	"Comment ID"
+// @Param			commentUpdateRequest	body		commentUpdateRequest	true	"Request"
+// @Success		200						{object}	response
+// @Failure		400,401,403,404,500		{object}	errorResponse
+// @Router			/comments/{id} [put]
+func (h *Handler) commentUpdate(ctx *fiber.Ctx) error {
+	var err error
+
+	var req commentUpdateRequest
+	if err = ctx.BodyParser(&req); err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	if err = h.commentService.Update(ctx.Context(), objectID, ctxUser.ID, domain.CommentUpdateInput{
+		Text: req.Text,
+	}); err != nil {
+		if errors.Is(err, domain.ErrCommentNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+		if errors.Is(err, domain.ErrForbidden) {
+			return h.newResponse(ctx, fiber.StatusForbidden, err)
+		}
+		if errors.Is(err, domain.ErrBadRequest) || errors.Is(err, domain.ErrCommentRejected) {
+			return h.newResponse(ctx, fiber.StatusBadRequest, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
+
+// @Summary		Delete
+// @Description	Delete comment
+// @Security		UserAuth
+// @Tags			comments
+// @Accept			json
+// @Produce		json
+// @Param			id					path		string	true	"Comment ID"
+// @Success		200					{object}	response
+// @Failure		400,401,403,404,500	{object}	errorResponse
+// @Router			/comments/{id} [delete]
+func (h *Handler) commentDelete(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	if err = h.commentService.Delete(ctx.Context(), objectID, ctxUser.ID); err != nil {
+		if errors.Is(err, domain.ErrCommentNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+		if errors.Is(err, domain.ErrForbidden) {
+			return h.newResponse(ctx, fiber.StatusForbidden, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
+
+// @Summary		Hide
+// @Description	Hide comment (moderators only)
+// @Security		UserAuth
+// @Tags			comments
+// @Accept			json
+// @Produce		json
+// @Param			id					path		string	true	"Comment ID"
+// @Success		200					{object}	response
+// @Failure		400,401,403,404,500	{object}	errorResponse
+// @Router			/comments/{id}/hidden [put]
+func (h *Handler) commentHide(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	if err = h.commentService.Hide(ctx.Context(), objectID); err != nil {
+		if errors.Is(err, domain.ErrCommentNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
+
+// @Summary		Unhide
+// @Description	Unhide comment (moderators only)
+// @Security		UserAuth
+// @Tags			comments
+// @Accept			json
+// @Produce		json
+// @Param			id					path		string	true	"Comment ID"
+// @Success		200					{object}	response
+// @Failure		400,401,403,404,500	{object}	errorResponse
+// @Router			/comments/{id}/hidden [delete]
+func (h *Handler) commentUnhide(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	if err = h.commentService.Unhide(ctx.Context(), objectID); err != nil {
+		if errors.Is(err, domain.ErrCommentNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
@@ -0,0 +1,57 @@
+package v1
+
+import (
+	"github.com/Closi-App/backend/pkg/smtp"
+	"github.com/gofiber/fiber/v2"
+)
+
+// initDevRoutes registers routes for local development, they are available to admins only with app.dev enabled.
+// The email routes also require the memory email sender.
+func (h *Handler) initDevRoutes(router fiber.Router) {
+	if !h.isDev {
+		return
+	}
+
+	capturer, ok := h.emailSender.(smtp.Capturer)
+	if !ok {
+		return
+	}
+
+	dev := router.Group("/dev", h.userAuthMiddleware, h.adminMiddleware)
+	{
+		dev.Get("/emails", func(ctx *fiber.Ctx) error {
+			return h.devGetEmails(ctx, capturer)
+		})
+		dev.Delete("/emails", func(ctx *fiber.Ctx) error {
+			return h.devResetEmails(ctx, capturer)
+		})
+	}
+}
+
+// @Summary		Get sent emails
+// @Description	Get emails kept by the memory email sender, oldest first. Available only with app.dev and smtp.sender "memory"
+// @Security		UserAuth
+// @Tags			dev
+// @Accept			json
+// @Produce		json
+// @Success		200			{object}	successResponse
+// @Failure		401,403,404	{object}	errorResponse
+// @Router			/dev/emails [get]
+func (h *Handler) devGetEmails(ctx *fiber.Ctx, capturer smtp.Capturer) error {
+	return h.newResponse(ctx, fiber.StatusOK, capturer.Messages())
+}
+
+// @Summary		Reset sent emails
+// @Description	Remove emails kept by the memory email sender. Available only with app.dev and smtp.sender "memory"
+// @Security		UserAuth
+// @Tags			dev
+// @Accept			json
+// @Produce		json
+// @Success		200			{object}	response
+// @Failure		401,403,404	{object}	errorResponse
+// @Router			/dev/emails [delete]
+func (h *Handler) devResetEmails(ctx *fiber.Ctx, capturer smtp.Capturer) error {
+	capturer.Reset()
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
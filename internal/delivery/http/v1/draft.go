@@ -0,0 +1,266 @@
+package v1
+
+import (
+	"errors"
+	"github.com/Closi-App/backend/internal/domain"
+	"github.com/Closi-App/backend/internal/service"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"time"
+)
+
+func (h *Handler) initDraftRoutes(router fiber.Router) {
+	drafts := router.Group("/drafts", h.userAuthMiddleware)
+	{
+		drafts.Post("/", h.draftCreate)
+		drafts.Get("/", h.draftGetAll)
+		drafts.Get("/:id", h.draftGetByID)
+		drafts.Put("/:id", h.draftUpdate)
+		drafts.Delete("/:id", h.draftDelete)
+		drafts.Post("/:id/publish", h.draftPublish)
+	}
+}
+
+type draftSaveRequest struct {
+	Title          string   `json:"title"`
+	Description    string   `json:"description"`
+	AttachmentsURL []string `json:"attachments_url"`
+	Tags           []string `json:"tags"`
+	Points         uint     `json:"points"`
+}
+
+func (r draftSaveRequest) toInput() domain.QuestionDraftSaveInput {
+	return domain.QuestionDraftSaveInput{
+		Title:          r.Title,
+		Description:    r.Description,
+		AttachmentsURL: r.AttachmentsURL,
+		Tags:           r.Tags,
+		Points:         r.Points,
+	}
+}
+
+// @Summary		Create
+// @Description	Create new question draft
+// @Security		UserAuth
+// @Tags			drafts
+// @Accept			json
+// @Produce		json
+// @Param			draftSaveRequest	body		draftSaveRequest	true	"Request"
+// @Success		201					{object}	successResponse
+// @Failure		400,401,500			{object}	errorResponse
+// @Router			/drafts [post]
+func (h *Handler) draftCreate(ctx *fiber.Ctx) error {
+	var req draftSaveRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	id, err := h.questionDraftService.Create(ctx.Context(), service.QuestionDraftCreateInput{
+		QuestionDraftSaveInput: req.toInput(),
+		CountryID:              ctxUser.Settings.CountryID,
+		UserID:                 ctxUser.ID,
+	})
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusCreated, idResponse{id.Hex()})
+}
+
+// @Summary		Get all
+// @Description	Get all drafts of the current user
+// @Security		UserAuth
+// @Tags			drafts
+// @Accept			json
+// @Produce		json
+// @Success		200		{object}	successResponse
+// @Failure		401,500	{object}	errorResponse
+// @Router			/drafts [get]
+func (h *Handler) draftGetAll(ctx *fiber.Ctx) error {
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	drafts, err := h.questionDraftService.GetAllByUserID(ctx.Context(), ctxUser.ID)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK, drafts)
+}
+
+// @Summary		Get by ID
+// @Description	Get draft of the current user by ID
+// @Security		UserAuth
+// @Tags			drafts
+// @Accept			json
+// @Produce		json
+// @Param			id				path		string	true	"Draft ID"
+// @Success		200				{object}	successResponse
+// @Failure		400,401,404,500	{object}	errorResponse
+// @Router			/drafts/{id} [get]
+func (h *Handler) draftGetByID(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	draft, err := h.questionDraftService.GetByID(ctx.Context(), objectID, ctxUser.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrDraftNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK, draft)
+}
+
+// @Summary		Update
+// @Description	Autosave draft content
+// @Security		UserAuth
+// @Tags			drafts
+// @Accept			json
+// @Produce		json
+// @Param			id					path		string				true	"Draft ID"
+// @Param			draftSaveRequest	body		draftSaveRequest	true	"Request"
+// @Success		200					{object}	response
+// @Failure		400,401,404,500		{object}	errorResponse
+// @Router			/drafts/{id} [put]
+func (h *Handler) draftUpdate(ctx *fiber.Ctx) error {
+	var req draftSaveRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	if err = h.questionDraftService.Update(ctx.Context(), objectID, ctxUser.ID, req.toInput()); err != nil {
+		if errors.Is(err, domain.ErrDraftNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
+
+// @Summary		Delete
+// @Description	Delete draft
+// @Security		UserAuth
+// @Tags			drafts
+// @Accept			json
+// @Produce		json
+// @Param			id				path		string	true	"Draft ID"
+// @Success		200				{object}	response
+// @Failure		400,401,404,500	{object}	errorResponse
+// @Router			/drafts/{id} [delete]
+func (h *Handler) draftDelete(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	if err = h.questionDraftService.Delete(ctx.Context(), objectID, ctxUser.ID); err != nil {
+		if errors.Is(err, domain.ErrDraftNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
+
+type draftPublishRequest struct {
+	PublishAt *time.Time `json:"publish_at"`
+}
+
+// @Summary		Publish
+// @Description	Publish draft as a question now or schedule it for publish_at
+// @Security		UserAuth
+// @Tags			drafts
+// @Accept			json
+// @Produce		json
+// @Param			id					path		string				true	"Draft ID"
+// @Param			draftPublishRequest	body		draftPublishRequest	false	"Request"
+// @Success		200,201				{object}	successResponse
+// @Failure		400,401,404,500		{object}	errorResponse
+// @Router			/drafts/{id}/publish [post]
+func (h *Handler) draftPublish(ctx *fiber.Ctx) error {
+	var req draftPublishRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&req); err != nil {
+			return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+		}
+	}
+
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	if req.PublishAt != nil {
+		if err = h.questionDraftService.Schedule(ctx.Context(), objectID, ctxUser.ID, *req.PublishAt); err != nil {
+			if errors.Is(err, domain.ErrDraftNotFound) {
+				return h.newResponse(ctx, fiber.StatusNotFound, err)
+			}
+			if errors.Is(err, domain.ErrDraftInvalidPublishTime) {
+				return h.newResponse(ctx, fiber.StatusBadRequest, err)
+			}
+
+			return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusOK)
+	}
+
+	questionID, err := h.questionDraftService.Publish(ctx.Context(), objectID, ctxUser.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrDraftNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+		if errors.Is(err, domain.ErrUserInsufficientPoints) || errors.Is(err, domain.ErrTagInvalidName) {
+			return h.newResponse(ctx, fiber.StatusBadRequest, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusCreated, idResponse{questionID.Hex()})
+}
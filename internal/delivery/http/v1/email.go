@@ -0,0 +1,272 @@
+package v1
+
+import (
+	"bytes"
+	"errors"
+	"github.com/Closi-App/backend/internal/domain"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"net/mail"
+	"net/url"
+)
+
+func (h *Handler) initEmailRoutes(router fiber.Router) {
+	emails := router.Group("/emails")
+	{
+		emails.Get("/unsubscribe", h.emailConfirmUnsubscribe)
+		emails.Post("/unsubscribe", h.emailUnsubscribe)
+
+		bounces := emails.Group("/bounces", h.emailWebhookMiddleware)
+		{
+			bounces.Post("", h.emailBounce)
+			bounces.Post("/report", h.emailBounceReport)
+		}
+
+		admin := emails.Group("", h.userAuthMiddleware, h.adminMiddleware)
+		{
+			admin.Get("/failed", h.emailGetFailed)
+			admin.Put("/:id/retry", h.emailRetry)
+			admin.Get("/preview/:type", h.emailPreview)
+			admin.Post("/preview/:type/send", h.emailSendPreview)
+		}
+	}
+}
+
+// @Summary		Confirm unsubscribe
+// @Description	Opened from the List-Unsubscribe link, redirects to email.unsubscribe_page_url with the token to confirm the unsubscribe
+// @Description	or turns off the category of notification emails right away if the page isn't set
+// @Tags			emails
+// @Produce		json
+// @Param			token	query		string	true	"Unsubscribe token"
+// @Success		200		{object}	response
+// @Success		303
+// @Failure		400,500	{object}	errorResponse
+// @Router			/emails/unsubscribe [get]
+func (h *Handler) emailConfirmUnsubscribe(ctx *fiber.Ctx) error {
+	token := ctx.Query("token")
+	if token == "" {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	if h.unsubscribePageURL == "" {
+		return h.emailUnsubscribe(ctx)
+	}
+
+	return ctx.Redirect(h.unsubscribePageURL+"?"+url.Values{"token": {token}}.Encode(), fiber.StatusSeeOther)
+}
+
+// @Summary		Unsubscribe
+// @Description	Turn off the category of notification emails from the signed link of List-Unsubscribe header
+// @Tags			emails
+// @Accept			json
+// @Produce		json
+// @Param			token	query		string	true	"Unsubscribe token"
+// @Success		200		{object}	response
+// @Failure		400,500	{object}	errorResponse
+// @Router			/emails/unsubscribe [post]
+func (h *Handler) emailUnsubscribe(ctx *fiber.Ctx) error {
+	token := ctx.Query("token")
+	if token == "" {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	if err := h.emailService.Unsubscribe(ctx.Context(), token); err != nil {
+		if errors.Is(err, domain.ErrEmailInvalidUnsubscribeToken) {
+			return h.newResponse(ctx, fiber.StatusBadRequest, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
+
+type emailBounceRequest struct {
+	Email  string `json:"email"`
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+}
+
+// @Summary		Bounce
+// @Description	Webhook for bounce and complaint notifications of the mail provider, type is hard_bounce, soft_bounce or complaint.
+// @Description	Hard bounces and complaints mark the user email as undeliverable, no emails are sent to it until the user changes it
+// @Tags			emails
+// @Accept			json
+// @Produce		json
+// @Param			X-Webhook-Secret	header		string				true	"Webhook secret"
+// @Param			input				body		emailBounceRequest	true	"Bounce request"
+// @Success		200					{object}	response
+// @Failure		400,401,500			{object}	errorResponse
+// @Router			/emails/bounces [post]
+func (h *Handler) emailBounce(ctx *fiber.Ctx) error {
+	var req emailBounceRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	address, err := mail.ParseAddress(req.Email)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	bounceType, err := domain.ParseEmailBounceType(req.Type)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, err)
+	}
+
+	if err = h.emailService.HandleBounce(ctx.Context(), domain.EmailBounceInput{
+		Email:  address.Address,
+		Type:   bounceType,
+		Reason: req.Reason,
+	}); err != nil {
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
+
+// @Summary		Bounce report
+// @Description	Webhook for raw delivery status notifications (RFC 3464) and complaint feedback reports (RFC 5965)
+// @Tags			emails
+// @Accept			plain
+// @Produce		json
+// @Param			X-Webhook-Secret	header		string	true	"Webhook secret"
+// @Param			input				body		string	true	"Report message"
+// @Success		200					{object}	response
+// @Failure		400,401,500			{object}	errorResponse
+// @Router			/emails/bounces/report [post]
+func (h *Handler) emailBounceReport(ctx *fiber.Ctx) error {
+	if err := h.emailService.HandleReport(ctx.Context(), bytes.NewReader(ctx.Body())); err != nil {
+		if errors.Is(err, domain.ErrBadRequest) {
+			return h.newResponse(ctx, fiber.StatusBadRequest, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
+
+// @Summary		Get failed
+// @Description	Get emails which weren't sent after all attempts, newest first. Only for admins
+// @Security		UserAuth
+// @Tags			emails
+// @Accept			json
+// @Produce		json
+// @Param			page		query		int	false	"Page number, starts with 1"
+// @Param			limit		query		int	false	"Page size"
+// @Success		200			{object}	successResponse
+// @Failure		401,403,500	{object}	errorResponse
+// @Router			/emails/failed [get]
+func (h *Handler) emailGetFailed(ctx *fiber.Ctx) error {
+	emails, err := h.emailService.GetAll(ctx.Context(), domain.EmailGetAllFilter{
+		Status: domain.FailedEmailStatus,
+		Page:   ctx.QueryInt("page", 1),
+		Limit:  ctx.QueryInt("limit"),
+	})
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK, emails)
+}
+
+// @Summary		Retry
+// @Description	Put failed email back to the outbox to be sent again. Only for admins
+// @Security		UserAuth
+// @Tags			emails
+// @Accept			json
+// @Produce		json
+// @Param			id					path		string	true	"Email ID"
+// @Success		200					{object}	response
+// @Failure		400,401,403,404,500	{object}	errorResponse
+// @Router			/emails/{id}/retry [put]
+func (h *Handler) emailRetry(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	if err = h.emailService.Retry(ctx.Context(), objectID); err != nil {
+		if errors.Is(err, domain.ErrEmailNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
+
+// @Summary		Preview
+// @Description	Render email template with sample data as HTML and plain text. Only for admins
+// @Security		UserAuth
+// @Tags			emails
+// @Accept			json
+// @Produce		json
+// @Param			type			path		string	true	"Email type"
+// @Param			language		query		string	true	"Language"
+// @Success		200				{object}	successResponse
+// @Failure		400,401,403,500	{object}	errorResponse
+// @Router			/emails/preview/{type} [get]
+func (h *Handler) emailPreview(ctx *fiber.Ctx) error {
+	emailType, err := domain.ParseEmailType(ctx.Params("type"))
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, err)
+	}
+
+	preview, err := h.emailService.Preview(emailType, ctx.Query("language"))
+	if err != nil {
+		if errors.Is(err, domain.ErrBadRequest) {
+			return h.newResponse(ctx, fiber.StatusBadRequest, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK, preview)
+}
+
+type emailSendPreviewRequest struct {
+	Email    string `json:"email"`
+	Language string `json:"language"`
+}
+
+// @Summary		Send preview
+// @Description	Send email template rendered with sample data to the given address right away. Only for admins
+// @Security		UserAuth
+// @Tags			emails
+// @Accept			json
+// @Produce		json
+// @Param			type			path		string					true	"Email type"
+// @Param			input			body		emailSendPreviewRequest	true	"Send preview request"
+// @Success		200				{object}	response
+// @Failure		400,401,403,500	{object}	errorResponse
+// @Router			/emails/preview/{type}/send [post]
+func (h *Handler) emailSendPreview(ctx *fiber.Ctx) error {
+	var req emailSendPreviewRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	address, err := mail.ParseAddress(req.Email)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	emailType, err := domain.ParseEmailType(ctx.Params("type"))
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, err)
+	}
+
+	if err = h.emailService.SendPreview(address.Address, emailType, req.Language); err != nil {
+		if errors.Is(err, domain.ErrBadRequest) {
+			return h.newResponse(ctx, fiber.StatusBadRequest, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
@@ -0,0 +1,150 @@
+package v1
+
+import (
+	"errors"
+	"github.com/Closi-App/backend/internal/domain"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func (h *Handler) initFollowRoutes(router fiber.Router) {
+	follows := router.Group("/follows", h.userAuthMiddleware)
+	{
+		follows.Get("/", h.followGetAll)
+		follows.Put("/:target/:id", h.follow)
+		follows.Delete("/:target/:id", h.unfollow)
+	}
+
+	router.Get("/feed", h.userAuthMiddleware, h.feedGet)
+}
+
+// @Summary		Get all
+// @Description	Get all users, tags and questions followed by the current user
+// @Security		UserAuth
+// @Tags			follows
+// @Accept			json
+// @Produce		json
+// @Success		200		{object}	successResponse
+// @Failure		401,500	{object}	errorResponse
+// @Router			/follows [get]
+func (h *Handler) followGetAll(ctx *fiber.Ctx) error {
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	follows, err := h.followService.GetAllByUserID(ctx.Context(), ctxUser.ID)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK, follows)
+}
+
+// @Summary		Follow
+// @Description	Follow user, tag or question to get its new content in the feed
+// @Security		UserAuth
+// @Tags			follows
+// @Accept			json
+// @Produce		json
+// @Param			target			path		string	true	"Target type (user, tag or question)"
+// @Param			id				path		string	true	"Target ID"
+// @Success		200				{object}	response
+// @Failure		400,401,404,500	{object}	errorResponse
+// @Router			/follows/{target}/{id} [put]
+func (h *Handler) follow(ctx *fiber.Ctx) error {
+	targetType, err := domain.ParseFollowTarget(ctx.Params("target"))
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, err)
+	}
+
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	if err = h.followService.Follow(ctx.Context(), ctxUser.ID, targetType, objectID); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) ||
+			errors.Is(err, domain.ErrTagNotFound) ||
+			errors.Is(err, domain.ErrQuestionNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+		if errors.Is(err, domain.ErrFollowSelf) {
+			return h.newResponse(ctx, fiber.StatusBadRequest, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
+
+// @Summary		Unfollow
+// @Description	Stop following user, tag or question
+// @Security		UserAuth
+// @Tags			follows
+// @Accept			json
+// @Produce		json
+// @Param			target		path		string	true	"Target type (user, tag or question)"
+// @Param			id			path		string	true	"Target ID"
+// @Success		200			{object}	response
+// @Failure		400,401,500	{object}	errorResponse
+// @Router			/follows/{target}/{id} [delete]
+func (h *Handler) unfollow(ctx *fiber.Ctx) error {
+	targetType, err := domain.ParseFollowTarget(ctx.Params("target"))
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, err)
+	}
+
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	if err = h.followService.Unfollow(ctx.Context(), ctxUser.ID, targetType, objectID); err != nil {
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
+
+// @Summary		Get feed
+// @Description	Get new questions in followed tags and from followed users, and new answers on followed questions, newest first
+// @Security		UserAuth
+// @Tags			follows
+// @Accept			json
+// @Produce		json
+// @Param			cursor		query		string	false	"Cursor of the next page from the previous response"
+// @Param			limit		query		int		false	"Page size"
+// @Success		200			{object}	successResponse
+// @Failure		400,401,500	{object}	errorResponse
+// @Router			/feed [get]
+func (h *Handler) feedGet(ctx *fiber.Ctx) error {
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	feed, err := h.feedService.Get(ctx.Context(), ctxUser.ID, ctx.Query("cursor"), ctx.QueryInt("limit"))
+	if err != nil {
+		if errors.Is(err, domain.ErrFeedInvalidCursor) {
+			return h.newResponse(ctx, fiber.StatusBadRequest, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK, feed)
+}
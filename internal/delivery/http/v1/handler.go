@@ -5,7 +5,9 @@ import (
 	"github.com/Closi-App/backend/pkg/auth"
 	"github.com/Closi-App/backend/pkg/localizer"
 	"github.com/Closi-App/backend/pkg/logger"
+	"github.com/Closi-App/backend/pkg/smtp"
 	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
 	"golang.org/x/text/language"
 )
 
@@ -14,24 +16,45 @@ type Handler struct {
 	localizer             *localizer.Localizer
 	countryService        service.CountryService
 	imageService          service.ImageService
+	emailService          service.EmailService
 	tagService            service.TagService
 	userService           service.UserService
 	questionService       service.QuestionService
 	answerService         service.AnswerService
+	commentService        service.CommentService
+	questionDraftService  service.QuestionDraftService
+	favoriteService       service.FavoriteService
+	followService         service.FollowService
+	feedService           service.FeedService
+	notificationService   service.NotificationService
+	realtimeService       service.RealtimeService
 	tokensManager         auth.TokensManager
+	emailSender           smtp.Sender
 	appSupportedLanguages []language.Tag
+	isDev                 bool
+	unsubscribePageURL    string
 }
 
 func NewHandler(
+	cfg *viper.Viper,
 	log *logger.Logger,
 	localizer *localizer.Localizer,
 	countryService service.CountryService,
 	imageService service.ImageService,
+	emailService service.EmailService,
 	tagService service.TagService,
 	userService service.UserService,
 	questionService service.QuestionService,
 	answerService service.AnswerService,
+	commentService service.CommentService,
+	questionDraftService service.QuestionDraftService,
+	favoriteService service.FavoriteService,
+	followService service.FollowService,
+	feedService service.FeedService,
+	notificationService service.NotificationService,
+	realtimeService service.RealtimeService,
 	tokensManager auth.TokensManager,
+	emailSender smtp.Sender,
 	appSupportedLanguages []language.Tag,
 ) *Handler {
 	return &Handler{
@@ -39,12 +62,23 @@ func NewHandler(
 		localizer:             localizer,
 		countryService:        countryService,
 		imageService:          imageService,
+		emailService:          emailService,
 		tagService:            tagService,
 		userService:           userService,
 		questionService:       questionService,
 		answerService:         answerService,
+		commentService:        commentService,
+		questionDraftService:  questionDraftService,
+		favoriteService:       favoriteService,
+		followService:         followService,
+		feedService:           feedService,
+		notificationService:   notificationService,
+		realtimeService:       realtimeService,
 		tokensManager:         tokensManager,
+		emailSender:           emailSender,
 		appSupportedLanguages: appSupportedLanguages,
+		isDev:                 cfg.GetBool("app.dev"),
+		unsubscribePageURL:    cfg.GetString("email.unsubscribe_page_url"),
 	}
 }
 
@@ -57,5 +91,12 @@ func (h *Handler) InitRoutes(router fiber.Router) {
 		h.initUserRoutes(v1)
 		h.initQuestionRoutes(v1)
 		h.initAnswerRoutes(v1)
+		h.initCommentRoutes(v1)
+		h.initDraftRoutes(v1)
+		h.initFollowRoutes(v1)
+		h.initNotificationRoutes(v1)
+		h.initRealtimeRoutes(v1)
+		h.initEmailRoutes(v1)
+		h.initDevRoutes(v1)
 	}
 }
@@ -15,6 +15,7 @@ import (
 const (
 	acceptLanguageHeader = "Accept-Language"
 	authorizationHeader  = "Authorization"
+	webhookSecretHeader  = "X-Webhook-Secret"
 
 	localizerCtxKey = "localizer"
 	userCtxKey      = "user"
@@ -66,42 +67,113 @@ func (h *Handler) setLocalizerToCtx(ctx *fiber.Ctx, lang string) error {
 }
 
 func (h *Handler) userAuthMiddleware(ctx *fiber.Ctx) error {
-	header := ctx.Get(authorizationHeader)
-	if header == "" {
+	objectID, err := h.parseAccessToken(ctx)
+	if err != nil {
 		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
 	}
 
-	headerParts := strings.Split(header, " ")
-	if len(headerParts) != 2 || headerParts[0] != "Bearer" {
+	user, err := h.userService.GetByID(ctx.Context(), objectID)
+	if err != nil {
 		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
 	}
 
-	accessToken := headerParts[1]
+	ctx.Locals(userCtxKey, user)
 
-	id, err := h.tokensManager.Parse(accessToken)
-	if err != nil {
-		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	if err = h.setLocalizerToCtx(ctx, user.Settings.Language); err != nil {
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
 	}
 
-	objectID, err := bson.ObjectIDFromHex(id)
+	return ctx.Next()
+}
+
+// moderatorMiddleware must be used after userAuthMiddleware.
+func (h *Handler) moderatorMiddleware(ctx *fiber.Ctx) error {
+	user, err := h.getUserFromCtx(ctx)
 	if err != nil {
 		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
 	}
 
-	user, err := h.userService.GetByID(ctx.Context(), objectID)
+	if !user.IsModerator() {
+		return h.newResponse(ctx, fiber.StatusForbidden, domain.ErrForbidden)
+	}
+
+	return ctx.Next()
+}
+
+// adminMiddleware must be used after userAuthMiddleware.
+func (h *Handler) adminMiddleware(ctx *fiber.Ctx) error {
+	user, err := h.getUserFromCtx(ctx)
 	if err != nil {
 		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
 	}
 
-	ctx.Locals(userCtxKey, user)
+	if !user.IsAdmin() {
+		return h.newResponse(ctx, fiber.StatusForbidden, domain.ErrForbidden)
+	}
 
-	if err = h.setLocalizerToCtx(ctx, user.Settings.Language); err != nil {
-		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	return ctx.Next()
+}
+
+// emailWebhookMiddleware checks the secret of the mail provider webhooks passed in the header,
+// it's never accepted in the query, so it doesn't end up in access logs.
+func (h *Handler) emailWebhookMiddleware(ctx *fiber.Ctx) error {
+	if err := h.emailService.VerifyWebhookSecret(ctx.Get(webhookSecretHeader)); err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, err)
 	}
 
 	return ctx.Next()
 }
 
+// parseAccessToken returns ID of the user from the bearer access token in the authorization header.
+func (h *Handler) parseAccessToken(ctx *fiber.Ctx) (bson.ObjectID, error) {
+	header := ctx.Get(authorizationHeader)
+	if header == "" {
+		return bson.ObjectID{}, domain.ErrUnauthorized
+	}
+
+	headerParts := strings.Split(header, " ")
+	if len(headerParts) != 2 || headerParts[0] != "Bearer" {
+		return bson.ObjectID{}, domain.ErrUnauthorized
+	}
+
+	id, err := h.tokensManager.Parse(headerParts[1])
+	if err != nil {
+		return bson.ObjectID{}, domain.ErrUnauthorized
+	}
+
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return bson.ObjectID{}, domain.ErrUnauthorized
+	}
+
+	return objectID, nil
+}
+
+// getViewer identifies the client for view counting: by user ID if the request
+// carries a valid access token, otherwise by IP address.
+func (h *Handler) getViewer(ctx *fiber.Ctx) string {
+	if id, err := h.parseAccessToken(ctx); err == nil {
+		return "user:" + id.Hex()
+	}
+
+	return "ip:" + ctx.IP()
+}
+
+// isViewerModerator reports whether the optional access token of a public route belongs to a moderator.
+func (h *Handler) isViewerModerator(ctx *fiber.Ctx) bool {
+	id, err := h.parseAccessToken(ctx)
+	if err != nil {
+		return false
+	}
+
+	user, err := h.userService.GetByID(ctx.Context(), id)
+	if err != nil {
+		return false
+	}
+
+	return user.IsModerator()
+}
+
 func (h *Handler) getUserFromCtx(ctx *fiber.Ctx) (domain.User, error) {
 	user, ok := ctx.Locals(userCtxKey).(domain.User)
 	if !ok {
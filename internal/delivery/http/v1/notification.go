@@ -0,0 +1,127 @@
+package v1
+
+import (
+	"errors"
+	"github.com/Closi-App/backend/internal/domain"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func (h *Handler) initNotificationRoutes(router fiber.Router) {
+	notifications := router.Group("/notifications", h.userAuthMiddleware)
+	{
+		notifications.Get("/", h.notificationGetAll)
+		notifications.Get("/unread-count", h.notificationCountUnread)
+		notifications.Put("/read", h.notificationMarkAllRead)
+		notifications.Put("/:id/read", h.notificationMarkRead)
+	}
+}
+
+// @Summary		Get all
+// @Description	Get notifications of the current user, newest first, with text in the user language
+// @Security		UserAuth
+// @Tags			notifications
+// @Accept			json
+// @Produce		json
+// @Param			unread	query		bool	false	"Only unread notifications"
+// @Param			page	query		int		false	"Page number, starts with 1"
+// @Param			limit	query		int		false	"Page size"
+// @Success		200		{object}	successResponse
+// @Failure		401,500	{object}	errorResponse
+// @Router			/notifications [get]
+func (h *Handler) notificationGetAll(ctx *fiber.Ctx) error {
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	notifications, err := h.notificationService.GetAll(ctx.Context(), domain.NotificationGetAllFilter{
+		UserID:     ctxUser.ID,
+		UnreadOnly: ctx.QueryBool("unread"),
+		Page:       ctx.QueryInt("page", 1),
+		Limit:      ctx.QueryInt("limit"),
+	}, ctxUser.Settings.Language)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK, notifications)
+}
+
+// @Summary		Count unread
+// @Description	Get the number of unread notifications of the current user
+// @Security		UserAuth
+// @Tags			notifications
+// @Accept			json
+// @Produce		json
+// @Success		200		{object}	successResponse
+// @Failure		401,500	{object}	errorResponse
+// @Router			/notifications/unread-count [get]
+func (h *Handler) notificationCountUnread(ctx *fiber.Ctx) error {
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	count, err := h.notificationService.CountUnread(ctx.Context(), ctxUser.ID)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK, countResponse{count})
+}
+
+// @Summary		Mark read
+// @Description	Mark notification of the current user as read
+// @Security		UserAuth
+// @Tags			notifications
+// @Accept			json
+// @Produce		json
+// @Param			id				path		string	true	"Notification ID"
+// @Success		200				{object}	response
+// @Failure		400,401,404,500	{object}	errorResponse
+// @Router			/notifications/{id}/read [put]
+func (h *Handler) notificationMarkRead(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	if err = h.notificationService.MarkRead(ctx.Context(), objectID, ctxUser.ID); err != nil {
+		if errors.Is(err, domain.ErrNotificationNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
+
+// @Summary		Mark all read
+// @Description	Mark all notifications of the current user as read
+// @Security		UserAuth
+// @Tags			notifications
+// @Accept			json
+// @Produce		json
+// @Success		200		{object}	response
+// @Failure		401,500	{object}	errorResponse
+// @Router			/notifications/read [put]
+func (h *Handler) notificationMarkAllRead(ctx *fiber.Ctx) error {
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	if err = h.notificationService.MarkAllRead(ctx.Context(), ctxUser.ID); err != nil {
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
@@ -6,19 +6,35 @@ import (
 	"github.com/Closi-App/backend/internal/service"
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"strings"
 )
 
 func (h *Handler) initQuestionRoutes(router fiber.Router) {
 	questions := router.Group("/questions")
 	{
 		questions.Get("/", h.questionGetAllWithFilter)
+		questions.Get("/similar", h.questionGetSimilar)
 		questions.Get("/:id", h.questionGetByID)
+		questions.Get("/:id/revisions", h.questionGetRevisions)
 
 		auth := questions.Group("", h.userAuthMiddleware)
 		{
 			auth.Post("/", h.questionCreate)
 			auth.Put("/:id", h.questionUpdate)
 			auth.Delete("/:id", h.questionDelete)
+			auth.Post("/:id/restore", h.questionRestore)
+			auth.Put("/:id/duplicate", h.questionMarkDuplicate)
+			auth.Delete("/:id/duplicate", h.questionUnmarkDuplicate)
+
+			auth.Put("/:id/votes", h.questionVote)
+			auth.Delete("/:id/votes", h.questionUnvote)
+
+			auth.Post("/:id/bounty", h.questionRaiseBounty)
+
+			moderator := auth.Group("", h.moderatorMiddleware)
+			{
+				moderator.Post("/:id/revisions/:revisionID/rollback", h.questionRollback)
+			}
 		}
 	}
 }
@@ -52,10 +68,6 @@ func (h *Handler) questionCreate(ctx *fiber.Ctx) error {
 		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
 	}
 
-	if ctxUser.Points < req.Points {
-		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrUserInsufficientPoints)
-	}
-
 	id, err := h.questionService.Create(ctx.Context(), service.QuestionCreateInput{
 		Title:          req.Title,
 		Description:    req.Description,
@@ -66,6 +78,10 @@ func (h *Handler) questionCreate(ctx *fiber.Ctx) error {
 		UserID:         ctxUser.ID,
 	})
 	if err != nil {
+		if errors.Is(err, domain.ErrUserInsufficientPoints) || errors.Is(err, domain.ErrTagInvalidName) {
+			return h.newResponse(ctx, fiber.StatusBadRequest, err)
+		}
+
 		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
 	}
 
@@ -81,6 +97,7 @@ func (h *Handler) questionCreate(ctx *fiber.Ctx) error {
 // @Param			tag			query		string	false	"Question tag"
 // @Param			countryID	query		string	false	"Country ID"
 // @Param			userID		query		string	false	"User ID"
+// @Param			sort		query		string	false	"Sort mode"	Enums(newest, top, hot, views)
 // @Success		200			{object}	successResponse
 // @Failure		400,500		{object}	errorResponse
 // @Router			/questions [get]
@@ -90,7 +107,9 @@ func (h *Handler) questionGetAllWithFilter(ctx *fiber.Ctx) error {
 	countryID := ctx.Query("country_id")
 	userID := ctx.Query("user_id")
 
-	var filter domain.QuestionGetAllFilter
+	filter := domain.QuestionGetAllFilter{
+		Sort: domain.ParseQuestionSort(ctx.Query("sort")),
+	}
 
 	if title != "" {
 		filter.Title = &title
@@ -128,8 +147,50 @@ func (h *Handler) questionGetAllWithFilter(ctx *fiber.Ctx) error {
 	return h.newResponse(ctx, fiber.StatusOK, questions)
 }
 
+// @Summary		Get similar
+// @Description	Get questions similar to the one being posted by title, description and tags
+// @Tags			questions
+// @Accept			json
+// @Produce		json
+// @Param			country_id	query		string	true	"Country ID"
+// @Param			title		query		string	false	"Question title"
+// @Param			description	query		string	false	"Question description"
+// @Param			tags		query		string	false	"Comma separated tag names"
+// @Success		200			{object}	successResponse
+// @Failure		400,500		{object}	errorResponse
+// @Router			/questions/similar [get]
+func (h *Handler) questionGetSimilar(ctx *fiber.Ctx) error {
+	countryID, err := bson.ObjectIDFromHex(ctx.Query("country_id"))
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	var tags []string
+	if ctx.Query("tags") != "" {
+		tags = strings.Split(ctx.Query("tags"), ",")
+	}
+
+	questions, err := h.questionService.GetSimilar(ctx.Context(), service.QuestionSimilarInput{
+		Title:       ctx.Query("title"),
+		Description: ctx.Query("description"),
+		Tags:        tags,
+		CountryID:   countryID,
+	})
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK, questions)
+}
+
+type questionDetailsResponse struct {
+	domain.Question
+	Comments []domain.Comment  `json:"comments"`
+	Related  []domain.Question `json:"related"`
+}
+
 // @Summary		Get by ID
-// @Description	Get question by ID
+// @Description	Get question by ID with its comments and related questions
 // @Tags			questions
 // @Accept			json
 // @Produce		json
@@ -153,7 +214,29 @@ func (h *Handler) questionGetByID(ctx *fiber.Ctx) error {
 		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
 	}
 
-	return h.newResponse(ctx, fiber.StatusOK, question)
+	if err = h.questionService.RecordView(ctx.Context(), question.ID, h.getViewer(ctx)); err != nil {
+		h.log.Error().Err(err).Msgf("error recording view of question (%s)", question.ID)
+	}
+
+	targetType := domain.QuestionTarget
+	comments, err := h.commentService.GetAll(ctx.Context(), domain.CommentGetAllFilter{
+		TargetType: &targetType,
+		TargetID:   &question.ID,
+	})
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	related, err := h.questionService.GetRelated(ctx.Context(), question.ID)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK, questionDetailsResponse{
+		Question: question,
+		Comments: comments,
+		Related:  related,
+	})
 }
 
 type questionUpdateRequest struct {
@@ -173,7 +256,7 @@ type questionUpdateRequest struct {
 // @Param			id						path		string					true	"Question ID"
 // @Param			questionUpdateRequest	body		questionUpdateRequest	true	"Request"
 // @Success		200						{object}	response
-// @Failure		400,401,500				{object}	errorResponse
+// @Failure		400,401,403,404,500		{object}	errorResponse
 // @Router			/questions/{id} [put]
 func (h *Handler) questionUpdate(ctx *fiber.Ctx) error {
 	var err error
@@ -197,16 +280,13 @@ func (h *Handler) questionUpdate(ctx *fiber.Ctx) error {
 	var tags []bson.ObjectID
 
 	if req.Tags != nil {
-		for _, tagName := range req.Tags {
-			tagID, err := h.tagService.Create(ctx.Context(), service.TagCreateInput{
-				Name:      tagName,
-				CountryID: ctxUser.Settings.CountryID,
-			})
-			if err != nil {
-				return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+		tags, err = h.tagService.GetOrCreateAll(ctx.Context(), req.Tags, ctxUser.Settings.CountryID)
+		if err != nil {
+			if errors.Is(err, domain.ErrTagInvalidName) {
+				return h.newResponse(ctx, fiber.StatusBadRequest, err)
 			}
 
-			tags = append(tags, tagID)
+			return h.newResponse(ctx, fiber.StatusInternalServerError, err)
 		}
 	}
 
@@ -217,6 +297,16 @@ func (h *Handler) questionUpdate(ctx *fiber.Ctx) error {
 		Tags:           tags,
 		Points:         req.Points,
 	}); err != nil {
+		if errors.Is(err, domain.ErrQuestionNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+		if errors.Is(err, domain.ErrForbidden) {
+			return h.newResponse(ctx, fiber.StatusForbidden, err)
+		}
+		if errors.Is(err, domain.ErrUserInsufficientPoints) {
+			return h.newResponse(ctx, fiber.StatusBadRequest, err)
+		}
+
 		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
 	}
 
@@ -229,9 +319,9 @@ func (h *Handler) questionUpdate(ctx *fiber.Ctx) error {
 // @Tags			questions
 // @Accept			json
 // @Produce		json
-// @Param			id			path		string	true	"Question ID"
-// @Success		200			{object}	response
-// @Failure		400,401,500	{object}	errorResponse
+// @Param			id					path		string	true	"Question ID"
+// @Success		200					{object}	response
+// @Failure		400,401,403,404,500	{object}	errorResponse
 // @Router			/questions/{id} [delete]
 func (h *Handler) questionDelete(ctx *fiber.Ctx) error {
 	id := ctx.Params("id")
@@ -246,6 +336,346 @@ func (h *Handler) questionDelete(ctx *fiber.Ctx) error {
 	}
 
 	if err = h.questionService.Delete(ctx.Context(), objectID, ctxUser.ID); err != nil {
+		if errors.Is(err, domain.ErrQuestionNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+		if errors.Is(err, domain.ErrForbidden) {
+			return h.newResponse(ctx, fiber.StatusForbidden, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
+
+type questionVoteRequest struct {
+	Value int `json:"value"`
+}
+
+// @Summary		Vote
+// @Description	Up vote (1) or down vote (-1) question
+// @Security		UserAuth
+// @Tags			questions
+// @Accept			json
+// @Produce		json
+// @Param			id					path		string				true	"Question ID"
+// @Param			questionVoteRequest	body		questionVoteRequest	true	"Request"
+// @Success		200					{object}	response
+// @Failure		400,401,404,500		{object}	errorResponse
+// @Router			/questions/{id}/votes [put]
+func (h *Handler) questionVote(ctx *fiber.Ctx) error {
+	var req questionVoteRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	value, err := domain.ParseVoteValue(req.Value)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, err)
+	}
+
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	if err = h.questionService.Vote(ctx.Context(), objectID, ctxUser.ID, value); err != nil {
+		if errors.Is(err, domain.ErrQuestionNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+		if errors.Is(err, domain.ErrVoteOwnQuestion) {
+			return h.newResponse(ctx, fiber.StatusBadRequest, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
+
+// @Summary		Unvote
+// @Description	Remove vote from question
+// @Security		UserAuth
+// @Tags			questions
+// @Accept			json
+// @Produce		json
+// @Param			id				path		string	true	"Question ID"
+// @Success		200				{object}	response
+// @Failure		400,401,404,500	{object}	errorResponse
+// @Router			/questions/{id}/votes [delete]
+func (h *Handler) questionUnvote(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	if err = h.questionService.Unvote(ctx.Context(), objectID, ctxUser.ID); err != nil {
+		if errors.Is(err, domain.ErrQuestionNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
+
+type questionRaiseBountyRequest struct {
+	Points uint `json:"points"`
+}
+
+// @Summary		Raise bounty
+// @Description	Add points to question bounty and extend its deadline
+// @Security		UserAuth
+// @Tags			questions
+// @Accept			json
+// @Produce		json
+// @Param			id							path		string						true	"Question ID"
+// @Param			questionRaiseBountyRequest	body		questionRaiseBountyRequest	true	"Request"
+// @Success		200							{object}	response
+// @Failure		400,401,403,404,409,500		{object}	errorResponse
+// @Router			/questions/{id}/bounty [post]
+func (h *Handler) questionRaiseBounty(ctx *fiber.Ctx) error {
+	var req questionRaiseBountyRequest
+	if err := ctx.BodyParser(&req); err != nil || req.Points == 0 {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	if err = h.questionService.RaiseBounty(ctx.Context(), objectID, ctxUser.ID, req.Points); err != nil {
+		if errors.Is(err, domain.ErrQuestionNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+		if errors.Is(err, domain.ErrForbidden) {
+			return h.newResponse(ctx, fiber.StatusForbidden, err)
+		}
+		if errors.Is(err, domain.ErrUserInsufficientPoints) {
+			return h.newResponse(ctx, fiber.StatusBadRequest, err)
+		}
+		if errors.Is(err, domain.ErrQuestionBountyClosed) {
+			return h.newResponse(ctx, fiber.StatusConflict, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
+
+// @Summary		Get revisions
+// @Description	Get question edit history with diffs between revisions
+// @Tags			questions
+// @Accept			json
+// @Produce		json
+// @Param			id			path		string	true	"Question ID"
+// @Success		200			{object}	successResponse
+// @Failure		400,404,500	{object}	errorResponse
+// @Router			/questions/{id}/revisions [get]
+func (h *Handler) questionGetRevisions(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	revisions, err := h.questionService.GetRevisions(ctx.Context(), objectID)
+	if err != nil {
+		if errors.Is(err, domain.ErrQuestionNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK, revisions)
+}
+
+// @Summary		Rollback
+// @Description	Rollback question to the revision (moderators only)
+// @Security		UserAuth
+// @Tags			questions
+// @Accept			json
+// @Produce		json
+// @Param			id					path		string	true	"Question ID"
+// @Param			revisionID			path		string	true	"Revision ID"
+// @Success		200					{object}	response
+// @Failure		400,401,403,404,500	{object}	errorResponse
+// @Router			/questions/{id}/revisions/{revisionID}/rollback [post]
+func (h *Handler) questionRollback(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	revisionID := ctx.Params("revisionID")
+	revisionObjectID, err := bson.ObjectIDFromHex(revisionID)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	if err = h.questionService.Rollback(ctx.Context(), objectID, revisionObjectID, ctxUser.ID); err != nil {
+		if errors.Is(err, domain.ErrQuestionNotFound) || errors.Is(err, domain.ErrRevisionNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
+
+// @Summary		Restore
+// @Description	Restore deleted question within the restore window (owner or moderator)
+// @Security		UserAuth
+// @Tags			questions
+// @Accept			json
+// @Produce		json
+// @Param			id					path		string	true	"Question ID"
+// @Success		200					{object}	response
+// @Failure		400,401,403,404,500	{object}	errorResponse
+// @Router			/questions/{id}/restore [post]
+func (h *Handler) questionRestore(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	if err = h.questionService.Restore(ctx.Context(), objectID, ctxUser.ID); err != nil {
+		if errors.Is(err, domain.ErrQuestionNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+		if errors.Is(err, domain.ErrForbidden) {
+			return h.newResponse(ctx, fiber.StatusForbidden, err)
+		}
+		if errors.Is(err, domain.ErrRestoreWindowExpired) {
+			return h.newResponse(ctx, fiber.StatusBadRequest, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
+
+type questionDuplicateRequest struct {
+	OriginalID string `json:"original_id"`
+}
+
+// @Summary		Mark duplicate
+// @Description	Mark question as a duplicate of the original question (owner or moderator)
+// @Security		UserAuth
+// @Tags			questions
+// @Accept			json
+// @Produce		json
+// @Param			id							path		string						true	"Question ID"
+// @Param			questionDuplicateRequest	body		questionDuplicateRequest	true	"Request"
+// @Success		200							{object}	response
+// @Failure		400,401,403,404,500			{object}	errorResponse
+// @Router			/questions/{id}/duplicate [put]
+func (h *Handler) questionMarkDuplicate(ctx *fiber.Ctx) error {
+	var req questionDuplicateRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	originalID, err := bson.ObjectIDFromHex(req.OriginalID)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	if err = h.questionService.MarkDuplicate(ctx.Context(), objectID, originalID, ctxUser.ID); err != nil {
+		if errors.Is(err, domain.ErrQuestionNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+		if errors.Is(err, domain.ErrForbidden) {
+			return h.newResponse(ctx, fiber.StatusForbidden, err)
+		}
+		if errors.Is(err, domain.ErrQuestionInvalidOriginal) {
+			return h.newResponse(ctx, fiber.StatusBadRequest, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
+
+// @Summary		Unmark duplicate
+// @Description	Remove duplicate mark from question (owner or moderator)
+// @Security		UserAuth
+// @Tags			questions
+// @Accept			json
+// @Produce		json
+// @Param			id					path		string	true	"Question ID"
+// @Success		200					{object}	response
+// @Failure		400,401,403,404,500	{object}	errorResponse
+// @Router			/questions/{id}/duplicate [delete]
+func (h *Handler) questionUnmarkDuplicate(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	if err = h.questionService.UnmarkDuplicate(ctx.Context(), objectID, ctxUser.ID); err != nil {
+		if errors.Is(err, domain.ErrQuestionNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+		if errors.Is(err, domain.ErrForbidden) {
+			return h.newResponse(ctx, fiber.StatusForbidden, err)
+		}
+
 		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
 	}
 
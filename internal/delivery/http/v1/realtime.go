@@ -0,0 +1,257 @@
+package v1
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Closi-App/backend/internal/domain"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"time"
+)
+
+const (
+	accessTokenQuery  = "access_token"
+	lastEventIDHeader = "Last-Event-ID"
+
+	realtimePingInterval = 30 * time.Second
+	realtimeWriteTimeout = 10 * time.Second
+
+	// streamRetry is the reconnection delay in milliseconds suggested to the event stream clients.
+	streamRetry = 3000
+)
+
+func (h *Handler) initRealtimeRoutes(router fiber.Router) {
+	router.Get("/ws", h.realtimeAuthMiddleware, h.realtimeUpgradeMiddleware, websocket.New(h.realtimeConnect))
+	router.Get("/stream", h.realtimeAuthMiddleware, h.realtimeStream)
+}
+
+// realtimeAuthMiddleware authenticates clients which can't set the authorization header (browser WebSocket
+// and EventSource), they may pass the access token in the access_token query parameter instead.
+func (h *Handler) realtimeAuthMiddleware(ctx *fiber.Ctx) error {
+	if token := ctx.Query(accessTokenQuery); token != "" && ctx.Get(authorizationHeader) == "" {
+		ctx.Request().Header.Set(authorizationHeader, "Bearer "+token)
+	}
+
+	return h.userAuthMiddleware(ctx)
+}
+
+func (h *Handler) realtimeUpgradeMiddleware(ctx *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(ctx) {
+		return h.newResponse(ctx, fiber.StatusUpgradeRequired, domain.ErrBadRequest)
+	}
+
+	return ctx.Next()
+}
+
+type realtimeReply struct {
+	Action  domain.RealtimeAction `json:"action"`
+	Channel string                `json:"channel"`
+	Error   string                `json:"error,omitempty"`
+}
+
+// @Summary		Connect
+// @Description	Open WebSocket connection for realtime events. Send {"action": "subscribe", "channel": "question:<id>"}
+// @Description	to subscribe to a question, "user:<own id>" for events concerning the current user or "notifications"
+// @Description	for new notifications, and "unsubscribe" action to stop. Events come as {"channel": "...", "event": {...}}.
+// @Security		UserAuth
+// @Tags			realtime
+// @Param			access_token	query	string	false	"Access token, if the authorization header can't be set"
+// @Success		101
+// @Failure		401,426	{object}	errorResponse
+// @Router			/ws [get]
+func (h *Handler) realtimeConnect(conn *websocket.Conn) {
+	user, ok := conn.Locals(userCtxKey).(domain.User)
+	if !ok {
+		return
+	}
+
+	subscriber := h.realtimeService.Connect(user.ID)
+	replies := make(chan realtimeReply, domain.RealtimeBufferSize)
+	writerDone := make(chan struct{})
+
+	go func() {
+		defer close(writerDone)
+		h.realtimeWrite(conn, subscriber.Messages(), replies)
+	}()
+
+	// the connection is released after the handler returns, so the writer must be stopped first
+	defer func() {
+		subscriber.Close()
+		close(replies)
+		<-writerDone
+	}()
+
+	for {
+		var command domain.RealtimeCommand
+		if err := conn.ReadJSON(&command); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				h.log.Warn().Err(err).Msgf("realtime connection of user (%s) is closed", user.ID)
+			}
+			return
+		}
+
+		var err error
+
+		switch command.Action {
+		case domain.SubscribeRealtimeAction:
+			err = subscriber.Subscribe(command.Channel)
+		case domain.UnsubscribeRealtimeAction:
+			err = subscriber.Unsubscribe(command.Channel)
+		default:
+			err = domain.ErrBadRequest
+		}
+
+		reply := realtimeReply{
+			Action:  command.Action,
+			Channel: command.Channel,
+		}
+
+		var appErr *domain.Error
+		if errors.As(err, &appErr) {
+			reply.Error = appErr.Code
+		}
+
+		select {
+		case replies <- reply:
+		default:
+			// the client doesn't read its replies
+			return
+		}
+	}
+}
+
+// realtimeWrite is the only writer of the connection, it sends events and replies and keeps
+// the connection alive with pings.
+func (h *Handler) realtimeWrite(conn *websocket.Conn, messages <-chan domain.RealtimeMessage, replies <-chan realtimeReply) {
+	ticker := time.NewTicker(realtimePingInterval)
+	defer ticker.Stop()
+
+	for {
+		var v any
+
+		select {
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			v = message
+		case reply, ok := <-replies:
+			if !ok {
+				return
+			}
+			v = reply
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(realtimeWriteTimeout)); err != nil {
+				return
+			}
+			continue
+		}
+
+		_ = conn.SetWriteDeadline(time.Now().Add(realtimeWriteTimeout))
+		if err := conn.WriteJSON(v); err != nil {
+			return
+		}
+	}
+}
+
+// @Summary		Stream
+// @Description	Open Server-Sent Events stream of the current user notifications ("notification_created" events)
+// @Description	and points balance changes ("points_changed" events). Send Last-Event-ID header to resume the stream,
+// @Description	recent events are kept for a day. The stream is closed after the server write timeout, clients reconnect
+// @Description	and resume it with Last-Event-ID.
+// @Security		UserAuth
+// @Tags			realtime
+// @Produce		text/event-stream
+// @Param			access_token	query	string	false	"Access token, if the authorization header can't be set"
+// @Param			Last-Event-ID	header	string	false	"ID of the last received event"
+// @Success		200
+// @Failure		400,401,500	{object}	errorResponse
+// @Router			/stream [get]
+func (h *Handler) realtimeStream(ctx *fiber.Ctx) error {
+	user, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	subscriber, missed, err := h.realtimeService.OpenStream(ctx.Context(), user.ID, ctx.Get(lastEventIDHeader))
+	if err != nil {
+		if errors.Is(err, domain.ErrRealtimeInvalidEventID) {
+			return h.newResponse(ctx, fiber.StatusBadRequest, err)
+		}
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	ctx.Set(fiber.HeaderContentType, "text/event-stream")
+	ctx.Set(fiber.HeaderCacheControl, "no-cache")
+	ctx.Set(fiber.HeaderConnection, "keep-alive")
+	ctx.Set("X-Accel-Buffering", "no")
+
+	// closed on server shutdown, disconnected clients are detected by failed writes
+	done := ctx.Context().Done()
+
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer subscriber.Close()
+
+		fmt.Fprintf(w, "retry: %d\n\n", streamRetry)
+
+		// the missed events may come again from the subscription
+		sent := make(map[string]struct{}, len(missed))
+
+		for _, message := range missed {
+			if err := h.writeStreamEvent(w, message); err != nil {
+				return
+			}
+			sent[message.ID] = struct{}{}
+		}
+
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		ticker := time.NewTicker(realtimePingInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case message, ok := <-subscriber.Messages():
+				if !ok {
+					return
+				}
+
+				if _, ok = sent[message.ID]; ok {
+					continue
+				}
+
+				if err := h.writeStreamEvent(w, message); err != nil {
+					return
+				}
+			case <-ticker.C:
+				// comment line keeps the connection alive and detects disconnected clients
+				if _, err := w.WriteString(": ping\n\n"); err != nil {
+					return
+				}
+			}
+
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+
+	return nil
+}
+
+func (h *Handler) writeStreamEvent(w *bufio.Writer, message domain.RealtimeMessage) error {
+	data, err := json.Marshal(message.Event)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", message.ID, message.Event.Type, data)
+
+	return err
+}
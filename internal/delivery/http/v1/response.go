@@ -42,6 +42,10 @@ type urlResponse struct {
 	URL string `json:"url"`
 }
 
+type countResponse struct {
+	Count int64 `json:"count"`
+}
+
 func (h *Handler) newSuccessResponse(ctx *fiber.Ctx, res response, data interface{}) error {
 	return ctx.Status(res.StatusCode).JSON(successResponse{
 		response: res,
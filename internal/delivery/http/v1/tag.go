@@ -10,20 +10,35 @@ import (
 func (h *Handler) initTagRoutes(router fiber.Router) {
 	tags := router.Group("/tags")
 	{
+		tags.Get("/autocomplete", h.tagAutocomplete)
+		tags.Get("/trending", h.tagGetTrending)
 		tags.Get("/:id", h.tagGetByID)
 		tags.Get("/", h.tagGetAll)
 
 		auth := tags.Group("", h.userAuthMiddleware)
 		{
 			auth.Get("/country/:countryID", h.tagGetAllByCountryID)
+
+			moderator := auth.Group("", h.moderatorMiddleware)
+			{
+				moderator.Put("/:id", h.tagUpdate)
+				moderator.Post("/:id/synonyms", h.tagAddSynonym)
+				moderator.Delete("/:id/synonyms/:name", h.tagRemoveSynonym)
+				moderator.Post("/:id/merge", h.tagMerge)
+			}
 		}
 
 		// TODO: create, delete function for admins
 	}
 }
 
+type tagDetailsResponse struct {
+	domain.Tag
+	TopQuestions []domain.Question `json:"top_questions"`
+}
+
 // @Summary		Get by ID
-// @Description	Get tag by ID
+// @Description	Get tag by ID with its top questions
 // @Tags			tags
 // @Accept			json
 // @Produce		json
@@ -47,7 +62,19 @@ func (h *Handler) tagGetByID(ctx *fiber.Ctx) error {
 		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
 	}
 
-	return h.newResponse(ctx, fiber.StatusOK, tag)
+	topQuestions, err := h.questionService.GetAll(ctx.Context(), domain.QuestionGetAllFilter{
+		Tag:   &tag.ID,
+		Sort:  domain.TopQuestionSort,
+		Limit: domain.TagTopQuestionsLimit,
+	})
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK, tagDetailsResponse{
+		Tag:          tag,
+		TopQuestions: topQuestions,
+	})
 }
 
 // @Summary		Get all
@@ -55,7 +82,7 @@ func (h *Handler) tagGetByID(ctx *fiber.Ctx) error {
 // @Tags			tags
 // @Accept			json
 // @Produce		json
-// @Success		200 {object}	successResponse
+// @Success		200	{object}	successResponse
 // @Failure		500	{object}	errorResponse
 // @Router			/tags [get]
 func (h *Handler) tagGetAll(ctx *fiber.Ctx) error {
@@ -90,3 +117,222 @@ func (h *Handler) tagGetAllByCountryID(ctx *fiber.Ctx) error {
 
 	return h.newResponse(ctx, fiber.StatusOK, tags)
 }
+
+// @Summary		Autocomplete
+// @Description	Get the most used tags of the country starting with the query
+// @Tags			tags
+// @Accept			json
+// @Produce		json
+// @Param			country_id	query		string	true	"Country ID"
+// @Param			q			query		string	false	"Tag name prefix"
+// @Param			limit		query		int		false	"Tags limit"
+// @Success		200			{object}	successResponse
+// @Failure		400,500		{object}	errorResponse
+// @Router			/tags/autocomplete [get]
+func (h *Handler) tagAutocomplete(ctx *fiber.Ctx) error {
+	countryID, err := bson.ObjectIDFromHex(ctx.Query("country_id"))
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	tags, err := h.tagService.Autocomplete(ctx.Context(), countryID, ctx.Query("q"), ctx.QueryInt("limit"))
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK, tags)
+}
+
+// @Summary		Get trending
+// @Description	Get tags of the country used by the most questions within the trending window
+// @Tags			tags
+// @Accept			json
+// @Produce		json
+// @Param			country_id	query		string	true	"Country ID"
+// @Param			limit		query		int		false	"Tags limit"
+// @Success		200			{object}	successResponse
+// @Failure		400,500		{object}	errorResponse
+// @Router			/tags/trending [get]
+func (h *Handler) tagGetTrending(ctx *fiber.Ctx) error {
+	countryID, err := bson.ObjectIDFromHex(ctx.Query("country_id"))
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	tags, err := h.tagService.GetTrending(ctx.Context(), countryID, ctx.QueryInt("limit"))
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK, tags)
+}
+
+type tagUpdateRequest struct {
+	Name         *string           `json:"name"`
+	Descriptions map[string]string `json:"descriptions"`
+}
+
+// @Summary		Update
+// @Description	Rename tag and set its descriptions by language, empty description removes it (moderators only)
+// @Security		UserAuth
+// @Tags			tags
+// @Accept			json
+// @Produce		json
+// @Param			id						path		string				true	"Tag ID"
+// @Param			tagUpdateRequest		body		tagUpdateRequest	true	"Request"
+// @Success		200						{object}	response
+// @Failure		400,401,403,404,409,500	{object}	errorResponse
+// @Router			/tags/{id} [put]
+func (h *Handler) tagUpdate(ctx *fiber.Ctx) error {
+	var req tagUpdateRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	if err = h.tagService.Update(ctx.Context(), objectID, domain.TagUpdateInput{
+		Name:         req.Name,
+		Descriptions: req.Descriptions,
+	}); err != nil {
+		if errors.Is(err, domain.ErrTagNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+		if errors.Is(err, domain.ErrTagAlreadyExists) {
+			return h.newResponse(ctx, fiber.StatusConflict, err)
+		}
+		if errors.Is(err, domain.ErrTagInvalidName) || errors.Is(err, domain.ErrBadRequest) {
+			return h.newResponse(ctx, fiber.StatusBadRequest, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
+
+type tagSynonymRequest struct {
+	Name string `json:"name"`
+}
+
+// @Summary		Add synonym
+// @Description	Add synonym which redirects to the tag on question creation (moderators only)
+// @Security		UserAuth
+// @Tags			tags
+// @Accept			json
+// @Produce		json
+// @Param			id						path		string				true	"Tag ID"
+// @Param			tagSynonymRequest		body		tagSynonymRequest	true	"Request"
+// @Success		200						{object}	response
+// @Failure		400,401,403,404,409,500	{object}	errorResponse
+// @Router			/tags/{id}/synonyms [post]
+func (h *Handler) tagAddSynonym(ctx *fiber.Ctx) error {
+	var req tagSynonymRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	if err = h.tagService.AddSynonym(ctx.Context(), objectID, req.Name); err != nil {
+		if errors.Is(err, domain.ErrTagNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+		if errors.Is(err, domain.ErrTagAlreadyExists) {
+			return h.newResponse(ctx, fiber.StatusConflict, err)
+		}
+		if errors.Is(err, domain.ErrTagInvalidName) || errors.Is(err, domain.ErrBadRequest) {
+			return h.newResponse(ctx, fiber.StatusBadRequest, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
+
+// @Summary		Remove synonym
+// @Description	Remove tag synonym (moderators only)
+// @Security		UserAuth
+// @Tags			tags
+// @Accept			json
+// @Produce		json
+// @Param			id					path		string	true	"Tag ID"
+// @Param			name				path		string	true	"Synonym"
+// @Success		200					{object}	response
+// @Failure		400,401,403,404,500	{object}	errorResponse
+// @Router			/tags/{id}/synonyms/{name} [delete]
+func (h *Handler) tagRemoveSynonym(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	if err = h.tagService.RemoveSynonym(ctx.Context(), objectID, ctx.Params("name")); err != nil {
+		if errors.Is(err, domain.ErrTagNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
+
+type tagMergeRequest struct {
+	TargetID string `json:"target_id"`
+}
+
+// @Summary		Merge
+// @Description	Merge tag into the target tag, the tag becomes a synonym of the target (moderators only)
+// @Security		UserAuth
+// @Tags			tags
+// @Accept			json
+// @Produce		json
+// @Param			id						path		string			true	"Tag ID"
+// @Param			tagMergeRequest			body		tagMergeRequest	true	"Request"
+// @Success		200						{object}	response
+// @Failure		400,401,403,404,409,500	{object}	errorResponse
+// @Router			/tags/{id}/merge [post]
+func (h *Handler) tagMerge(ctx *fiber.Ctx) error {
+	var req tagMergeRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	targetID, err := bson.ObjectIDFromHex(req.TargetID)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	id := ctx.Params("id")
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+	}
+
+	if err = h.tagService.Merge(ctx.Context(), objectID, targetID); err != nil {
+		if errors.Is(err, domain.ErrTagNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+		if errors.Is(err, domain.ErrTagAlreadyExists) {
+			return h.newResponse(ctx, fiber.StatusConflict, err)
+		}
+		if errors.Is(err, domain.ErrTagInvalidName) || errors.Is(err, domain.ErrBadRequest) {
+			return h.newResponse(ctx, fiber.StatusBadRequest, err)
+		}
+
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/Closi-App/backend/internal/utils"
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"time"
 )
 
 func (h *Handler) initUserRoutes(router fiber.Router) {
@@ -27,6 +28,7 @@ func (h *Handler) initUserRoutes(router fiber.Router) {
 
 			favorites := auth.Group("/favorites")
 			{
+				favorites.Get("/", h.userGetFavorites)
 				favorites.Post("/:questionID", h.userAddFavorite)
 				favorites.Delete("/:questionID", h.userRemoveFavorite)
 			}
@@ -243,10 +245,18 @@ func (h *Handler) userUpdate(ctx *fiber.Ctx) error {
 }
 
 type userUpdateSettingsRequest struct {
-	CountryID          *string
-	Language           *string
-	Appearance         *string
-	EmailNotifications *bool
+	CountryID        *string
+	Language         *string
+	Appearance       *string
+	Timezone         *string
+	EmailPreferences *userEmailPreferencesRequest
+}
+
+type userEmailPreferencesRequest struct {
+	Answers         *bool
+	AcceptedAnswers *bool
+	Bounties        *bool
+	Digest          *string
 }
 
 // @Summary		Update settings
@@ -289,17 +299,40 @@ func (h *Handler) userUpdateSettings(ctx *fiber.Ctx) error {
 	if req.Appearance != nil {
 		appearance = domain.ParseAppearance(*req.Appearance)
 	}
+	if req.Timezone != nil {
+		if _, err = time.LoadLocation(*req.Timezone); err != nil || *req.Timezone == "" {
+			return h.newResponse(ctx, fiber.StatusBadRequest, domain.ErrBadRequest)
+		}
+	}
 
 	ctxUser, err := h.getUserFromCtx(ctx)
 	if err != nil {
 		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
 	}
 
+	var emailPreferences *domain.EmailPreferencesUpdateInput
+	if req.EmailPreferences != nil {
+		emailPreferences = &domain.EmailPreferencesUpdateInput{
+			Answers:         req.EmailPreferences.Answers,
+			AcceptedAnswers: req.EmailPreferences.AcceptedAnswers,
+			Bounties:        req.EmailPreferences.Bounties,
+		}
+
+		if req.EmailPreferences.Digest != nil {
+			digest, err := domain.ParseDigestFrequency(*req.EmailPreferences.Digest)
+			if err != nil {
+				return h.newResponse(ctx, fiber.StatusBadRequest, err)
+			}
+			emailPreferences.Digest = &digest
+		}
+	}
+
 	if err = h.userService.UpdateSettings(ctx.Context(), ctxUser.ID, domain.UserSettingsUpdateInput{
-		CountryID:          &countryObjectID,
-		Language:           &lang,
-		Appearance:         &appearance,
-		EmailNotifications: req.EmailNotifications,
+		CountryID:        &countryObjectID,
+		Language:         &lang,
+		Appearance:       &appearance,
+		Timezone:         req.Timezone,
+		EmailPreferences: emailPreferences,
 	}); err != nil {
 		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
 	}
@@ -391,15 +424,44 @@ func (h *Handler) userConfirm(ctx *fiber.Ctx) error {
 	return h.newResponse(ctx, fiber.StatusOK)
 }
 
+// @Summary		Get favorites
+// @Description	Get favorite questions of auth user, the most recently added first
+// @Security		UserAuth
+// @Tags			users
+// @Accept			json
+// @Produce		json
+// @Param			page	query		int	false	"Page number, starts with 1"
+// @Param			limit	query		int	false	"Page size"
+// @Success		200		{object}	successResponse
+// @Failure		401,500	{object}	errorResponse
+// @Router			/users/favorites [get]
+func (h *Handler) userGetFavorites(ctx *fiber.Ctx) error {
+	ctxUser, err := h.getUserFromCtx(ctx)
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
+	}
+
+	favorites, err := h.favoriteService.GetAll(ctx.Context(), domain.FavoriteGetAllFilter{
+		UserID: ctxUser.ID,
+		Page:   ctx.QueryInt("page", 1),
+		Limit:  ctx.QueryInt("limit"),
+	})
+	if err != nil {
+		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
+	}
+
+	return h.newResponse(ctx, fiber.StatusOK, favorites)
+}
+
 // @Summary		Add favorite
 // @Description	Add favorite for auth user
 // @Security		UserAuth
 // @Tags			users
 // @Accept			json
 // @Produce		json
-// @Param			questionID	path		string	true	"Question ID"
-// @Success		200			{object}	response
-// @Failure		400,401,500	{object}	errorResponse
+// @Param			questionID		path		string	true	"Question ID"
+// @Success		200				{object}	response
+// @Failure		400,401,404,500	{object}	errorResponse
 // @Router			/users/favorites/{questionID} [post]
 func (h *Handler) userAddFavorite(ctx *fiber.Ctx) error {
 	questionID := ctx.Params("questionID")
@@ -413,7 +475,11 @@ func (h *Handler) userAddFavorite(ctx *fiber.Ctx) error {
 		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
 	}
 
-	if err = h.userService.AddFavorite(ctx.Context(), ctxUser.ID, questionObjectID); err != nil {
+	if err = h.favoriteService.Add(ctx.Context(), ctxUser.ID, questionObjectID); err != nil {
+		if errors.Is(err, domain.ErrQuestionNotFound) {
+			return h.newResponse(ctx, fiber.StatusNotFound, err)
+		}
+
 		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
 	}
 
@@ -442,7 +508,7 @@ func (h *Handler) userRemoveFavorite(ctx *fiber.Ctx) error {
 		return h.newResponse(ctx, fiber.StatusUnauthorized, domain.ErrUnauthorized)
 	}
 
-	if err = h.userService.RemoveFavorite(ctx.Context(), ctxUser.ID, questionObjectID); err != nil {
+	if err = h.favoriteService.Remove(ctx.Context(), ctxUser.ID, questionObjectID); err != nil {
 		return h.newResponse(ctx, fiber.StatusInternalServerError, err)
 	}
 
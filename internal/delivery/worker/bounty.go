@@ -0,0 +1,6 @@
+package worker
+
+func (w *Worker) initBountyJobs() {
+	w.addJob("resolve_bounties", w.cfg.GetDuration("worker.bounties_interval"), w.bountyService.ResolveExpired)
+	w.addJob("remind_bounties", w.cfg.GetDuration("worker.bounties_interval"), w.bountyService.RemindExpiring)
+}
@@ -0,0 +1,5 @@
+package worker
+
+func (w *Worker) initDigestJobs() {
+	w.addJob("send_digests", w.cfg.GetDuration("worker.digests_interval"), w.digestService.SendDue)
+}
@@ -0,0 +1,5 @@
+package worker
+
+func (w *Worker) initDraftJobs() {
+	w.addJob("publish_drafts", w.cfg.GetDuration("worker.drafts_publish_interval"), w.draftService.PublishScheduled)
+}
@@ -0,0 +1,5 @@
+package worker
+
+func (w *Worker) initEmailJobs() {
+	w.addJob("deliver_emails", w.cfg.GetDuration("worker.emails_interval"), w.emailService.DeliverDue)
+}
@@ -0,0 +1,5 @@
+package worker
+
+func (w *Worker) initPurgeJobs() {
+	w.addJob("purge_deleted", w.cfg.GetDuration("worker.purge_interval"), w.purgeService.PurgeDeleted)
+}
@@ -0,0 +1,5 @@
+package worker
+
+func (w *Worker) initRealtimeListeners() {
+	w.addListener("realtime_fanout", w.realtimeService.Listen)
+}
@@ -0,0 +1,5 @@
+package worker
+
+func (w *Worker) initViewJobs() {
+	w.addJob("flush_views", w.cfg.GetDuration("worker.views_flush_interval"), w.questionService.FlushViews)
+}
@@ -0,0 +1,170 @@
+package worker
+
+import (
+	"context"
+	"github.com/Closi-App/backend/internal/service"
+	"github.com/Closi-App/backend/pkg/logger"
+	"github.com/spf13/viper"
+	"sync"
+	"time"
+)
+
+// listenerRetryDelay is the pause before restarting a failed listener.
+const listenerRetryDelay = 5 * time.Second
+
+type Worker struct {
+	log             *logger.Logger
+	cfg             *viper.Viper
+	purgeService    service.PurgeService
+	questionService service.QuestionService
+	draftService    service.QuestionDraftService
+	bountyService   service.BountyService
+	digestService   service.DigestService
+	emailService    service.EmailService
+	realtimeService service.RealtimeService
+	jobs            []job
+	listeners       []listener
+	cancel          context.CancelFunc
+	wg              sync.WaitGroup
+}
+
+type job struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context) error
+}
+
+// listener is a long-running task, it runs until the worker is stopped and is restarted on errors.
+type listener struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+func NewWorker(
+	cfg *viper.Viper,
+	log *logger.Logger,
+	purgeService service.PurgeService,
+	questionService service.QuestionService,
+	draftService service.QuestionDraftService,
+	bountyService service.BountyService,
+	digestService service.DigestService,
+	emailService service.EmailService,
+	realtimeService service.RealtimeService,
+) *Worker {
+	w := &Worker{
+		log:             log,
+		cfg:             cfg,
+		purgeService:    purgeService,
+		questionService: questionService,
+		draftService:    draftService,
+		bountyService:   bountyService,
+		digestService:   digestService,
+		emailService:    emailService,
+		realtimeService: realtimeService,
+	}
+
+	w.initPurgeJobs()
+	w.initViewJobs()
+	w.initDraftJobs()
+	w.initBountyJobs()
+	w.initDigestJobs()
+	w.initEmailJobs()
+	w.initRealtimeListeners()
+
+	return w
+}
+
+func (w *Worker) addJob(name string, interval time.Duration, run func(ctx context.Context) error) {
+	if interval <= 0 {
+		w.log.Warn().Msgf("job %s is disabled: interval is not set", name)
+		return
+	}
+
+	w.jobs = append(w.jobs, job{
+		name:     name,
+		interval: interval,
+		run:      run,
+	})
+}
+
+func (w *Worker) addListener(name string, run func(ctx context.Context) error) {
+	w.listeners = append(w.listeners, listener{
+		name: name,
+		run:  run,
+	})
+}
+
+func (w *Worker) Start(ctx context.Context) error {
+	ctx, w.cancel = context.WithCancel(ctx)
+
+	for _, j := range w.jobs {
+		w.wg.Add(1)
+		go w.runJob(ctx, j)
+	}
+
+	for _, l := range w.listeners {
+		w.wg.Add(1)
+		go w.runListener(ctx, l)
+	}
+
+	return nil
+}
+
+func (w *Worker) Stop(ctx context.Context) error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *Worker) runListener(ctx context.Context, l listener) {
+	defer w.wg.Done()
+
+	for {
+		if err := l.run(ctx); err != nil {
+			w.log.Error().
+				Err(err).
+				Str("listener", l.name).
+				Msg("error running listener")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenerRetryDelay):
+		}
+	}
+}
+
+func (w *Worker) runJob(ctx context.Context, j job) {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := j.run(ctx); err != nil {
+				w.log.Error().
+					Err(err).
+					Str("job", j.name).
+					Msg("error running job")
+			}
+		}
+	}
+}
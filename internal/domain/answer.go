@@ -6,7 +6,9 @@ import (
 )
 
 var (
-	ErrAnswerNotFound = NewError("ERR_ANSWER_NOT_FOUND", "answer not found")
+	ErrAnswerNotFound        = NewError("ERR_ANSWER_NOT_FOUND", "answer not found")
+	ErrAnswerAlreadyVerified = NewError("ERR_ANSWER_ALREADY_VERIFIED", "question already has a verified answer")
+	ErrAnswerOwnLike         = NewError("ERR_ANSWER_OWN_LIKE", "you can't like your own answer")
 )
 
 const (
@@ -14,19 +16,25 @@ const (
 )
 
 type Answer struct {
-	ID         bson.ObjectID `bson:"_id" json:"id"`
-	Text       string        `bson:"text" json:"text"`
-	Likes      uint          `bson:"likes" json:"likes"`
-	QuestionID bson.ObjectID `bson:"question_id" json:"question_id"`
-	UserID     bson.ObjectID `bson:"user_id" json:"user_id"`
-	IsVerified bool          `bson:"is_verified" json:"is_verified"`
-	CreatedAt  time.Time     `bson:"created_at" json:"created_at"`
-	UpdatedAt  time.Time     `bson:"updated_at" json:"updated_at"`
+	ID         bson.ObjectID  `bson:"_id" json:"id"`
+	Text       string         `bson:"text" json:"text"`
+	Likes      uint           `bson:"likes" json:"likes"`
+	QuestionID bson.ObjectID  `bson:"question_id" json:"question_id"`
+	UserID     bson.ObjectID  `bson:"user_id" json:"user_id"`
+	IsVerified bool           `bson:"is_verified" json:"is_verified"`
+	CreatedAt  time.Time      `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time      `bson:"updated_at" json:"updated_at"`
+	DeletedAt  *time.Time     `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy  *bson.ObjectID `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"`
 }
 
 type AnswerGetAllFilter struct {
 	QuestionID *bson.ObjectID
 	UserID     *bson.ObjectID
+
+	// DeletedBefore selects only answers soft deleted before the time
+	DeletedBefore  *time.Time
+	IncludeDeleted bool
 }
 
 type AnswerUpdateInput struct {
@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+const (
+	HardEmailBounce EmailBounceType = "hard_bounce"
+	SoftEmailBounce EmailBounceType = "soft_bounce"
+	EmailComplaint  EmailBounceType = "complaint"
+)
+
+type EmailBounceType string
+
+func ParseEmailBounceType(bounceType string) (EmailBounceType, error) {
+	switch bounceType {
+	case "hard_bounce":
+		return HardEmailBounce, nil
+	case "soft_bounce":
+		return SoftEmailBounce, nil
+	case "complaint":
+		return EmailComplaint, nil
+	default:
+		return "", ErrBadRequest
+	}
+}
+
+// IsPermanent reports whether the address shouldn't be emailed anymore, soft bounces are retried by the outbox.
+func (t EmailBounceType) IsPermanent() bool {
+	return t == HardEmailBounce || t == EmailComplaint
+}
+
+// EmailBounceInput is a bounce or complaint notification about an email address received from the mail provider.
+type EmailBounceInput struct {
+	Email  string
+	Type   EmailBounceType
+	Reason string
+}
+
+// EmailBounce marks the user email as undeliverable, no emails are sent to it until the user changes it,
+// clients prompt the user to update their email while it's set.
+type EmailBounce struct {
+	Type      EmailBounceType `bson:"type" json:"type"`
+	Reason    string          `bson:"reason" json:"reason"`
+	CreatedAt time.Time       `bson:"created_at" json:"created_at"`
+}
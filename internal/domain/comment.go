@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"time"
+)
+
+var (
+	ErrCommentNotFound      = NewError("ERR_COMMENT_NOT_FOUND", "comment not found")
+	ErrCommentInvalidParent = NewError("ERR_COMMENT_INVALID_PARENT", "parent comment belongs to another target")
+	ErrCommentRejected      = NewError("ERR_COMMENT_REJECTED", "comment was rejected by moderation")
+)
+
+const (
+	CommentCollectionName = "comments"
+
+	CommentMaxLength = 600
+)
+
+type Comment struct {
+	ID         bson.ObjectID  `bson:"_id" json:"id"`
+	Text       string         `bson:"text" json:"text"`
+	TargetType TargetType     `bson:"target_type" json:"target_type"`
+	TargetID   bson.ObjectID  `bson:"target_id" json:"target_id"`
+	ParentID   *bson.ObjectID `bson:"parent_id,omitempty" json:"parent_id,omitempty"`
+	UserID     bson.ObjectID  `bson:"user_id" json:"user_id"`
+	IsHidden   bool           `bson:"is_hidden" json:"is_hidden"`
+	CreatedAt  time.Time      `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time      `bson:"updated_at" json:"updated_at"`
+}
+
+type CommentGetAllFilter struct {
+	TargetType    *TargetType
+	TargetID      *bson.ObjectID
+	UserID        *bson.ObjectID
+	IncludeHidden bool
+}
+
+type CommentUpdateInput struct {
+	Text *string
+}
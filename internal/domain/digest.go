@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"time"
+)
+
+const (
+	NoDigest     DigestFrequency = "off"
+	DailyDigest  DigestFrequency = "daily"
+	WeeklyDigest DigestFrequency = "weekly"
+
+	// DigestHour is the hour of the user local time when digests are sent.
+	DigestHour = 8
+	// DigestWeekday is the day of the week when weekly digests are sent.
+	DigestWeekday = time.Monday
+
+	DigestQuestionsLimit = 10
+	// DigestBatchSize is the amount of users handled by a single run of the digest job.
+	DigestBatchSize = 100
+)
+
+type DigestFrequency string
+
+func ParseDigestFrequency(frequency string) (DigestFrequency, error) {
+	switch frequency {
+	case "off":
+		return NoDigest, nil
+	case "daily":
+		return DailyDigest, nil
+	case "weekly":
+		return WeeklyDigest, nil
+	default:
+		return "", ErrBadRequest
+	}
+}
+
+func (f DigestFrequency) IsEnabled() bool {
+	return f == DailyDigest || f == WeeklyDigest
+}
+
+// Period returns how far back the digest looks.
+func (f DigestFrequency) Period() time.Duration {
+	if f == WeeklyDigest {
+		return 7 * 24 * time.Hour
+	}
+
+	return 24 * time.Hour
+}
+
+// NextAt returns the next time after now the digest is sent at in the user location.
+func (f DigestFrequency) NextAt(now time.Time, loc *time.Location) time.Time {
+	local := now.In(loc)
+	next := time.Date(local.Year(), local.Month(), local.Day(), DigestHour, 0, 0, 0, loc)
+
+	days := 1
+	if f == WeeklyDigest {
+		days = 7
+		next = next.AddDate(0, 0, (int(DigestWeekday)-int(next.Weekday())+7)%7)
+	}
+
+	if !next.After(now) {
+		next = next.AddDate(0, 0, days)
+	}
+
+	return next
+}
+
+// UserDigest is the digest schedule of the user, Points is the points balance reported in the last digest.
+type UserDigest struct {
+	NextAt *time.Time `bson:"next_at,omitempty"`
+	SentAt *time.Time `bson:"sent_at,omitempty"`
+	Points uint       `bson:"points"`
+}
+
+type DigestQuestionsFilter struct {
+	CountryID     bson.ObjectID
+	TagIDs        []bson.ObjectID
+	ExcludeUserID bson.ObjectID
+	Since         time.Time
+	Limit         int
+}
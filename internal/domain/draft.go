@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"time"
+)
+
+var (
+	ErrDraftNotFound           = NewError("ERR_DRAFT_NOT_FOUND", "draft not found")
+	ErrDraftInvalidPublishTime = NewError("ERR_DRAFT_INVALID_PUBLISH_TIME", "publish time must be in the future")
+)
+
+const (
+	QuestionDraftCollectionName = "question_drafts"
+)
+
+// QuestionDraft is an unpublished question, tags are kept as entered and resolved on publishing.
+type QuestionDraft struct {
+	ID             bson.ObjectID `bson:"_id" json:"id"`
+	Title          string        `bson:"title" json:"title"`
+	Description    string        `bson:"description" json:"description"`
+	AttachmentsURL []string      `bson:"attachments_url" json:"attachments_url"`
+	Tags           []string      `bson:"tags" json:"tags"`
+	Points         uint          `bson:"points" json:"points"`
+	CountryID      bson.ObjectID `bson:"country_id" json:"country_id"`
+	UserID         bson.ObjectID `bson:"user_id" json:"user_id"`
+	PublishAt      *time.Time    `bson:"publish_at,omitempty" json:"publish_at,omitempty"`
+	PublishError   string        `bson:"publish_error,omitempty" json:"publish_error,omitempty"`
+	CreatedAt      time.Time     `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time     `bson:"updated_at" json:"updated_at"`
+}
+
+// QuestionDraftSaveInput replaces the whole draft content on autosave.
+type QuestionDraftSaveInput struct {
+	Title          string
+	Description    string
+	AttachmentsURL []string
+	Tags           []string
+	Points         uint
+}
@@ -1,12 +1,56 @@
 package domain
 
+import (
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"strings"
+)
+
+var (
+	ErrEmailInvalidUnsubscribeToken = NewError("ERR_EMAIL_INVALID_UNSUBSCRIBE_TOKEN", "invalid unsubscribe link")
+)
+
 const (
 	WelcomeEmail      EmailType = "welcome"
 	ConfirmationEmail EmailType = "confirmation"
+
+	BountyAwardedEmail  EmailType = "bounty_awarded"
+	BountyReceivedEmail EmailType = "bounty_received"
+	BountyRefundedEmail EmailType = "bounty_refunded"
+	BountyExpiringEmail EmailType = "bounty_expiring"
+
+	NewAnswerEmail      EmailType = "new_answer"
+	AnswerAcceptedEmail EmailType = "answer_accepted"
+
+	DailyDigestEmail  EmailType = "daily_digest"
+	WeeklyDigestEmail EmailType = "weekly_digest"
+)
+
+const (
+	AnswersEmailCategory         EmailCategory = "answers"
+	AcceptedAnswersEmailCategory EmailCategory = "accepted_answers"
+	BountiesEmailCategory        EmailCategory = "bounties"
+	DigestsEmailCategory         EmailCategory = "digests"
 )
 
+// EmailTypes lists all email types, each of them must have a template in every supported language.
+var EmailTypes = []EmailType{
+	WelcomeEmail,
+	ConfirmationEmail,
+	BountyAwardedEmail,
+	BountyReceivedEmail,
+	BountyRefundedEmail,
+	BountyExpiringEmail,
+	NewAnswerEmail,
+	AnswerAcceptedEmail,
+	DailyDigestEmail,
+	WeeklyDigestEmail,
+}
+
 type EmailType string
 
+// EmailCategory groups notification emails, users turn them off by category.
+type EmailCategory string
+
 type (
 	WelcomeEmailData struct {
 		Name string
@@ -15,8 +59,144 @@ type (
 	ConfirmationEmailData struct {
 		ConfirmationLink string
 	}
+
+	BountyEmailData struct {
+		Name          string
+		QuestionTitle string
+		Points        uint
+	}
+
+	AnswerEmailData struct {
+		Name          string
+		ActorName     string
+		QuestionTitle string
+		Points        int
+	}
+
+	DigestEmailData struct {
+		Name            string
+		Weekly          bool
+		Questions       []Question
+		Answers         int64
+		Likes           int64
+		AcceptedAnswers int64
+		PointsChange    int
+		Points          uint
+	}
 )
 
+type EmailPreview struct {
+	Type     EmailType `json:"type"`
+	Language string    `json:"language"`
+	Subject  string    `json:"subject"`
+	HTML     string    `json:"html"`
+	Text     string    `json:"text"`
+}
+
+func ParseEmailType(emailType string) (EmailType, error) {
+	for _, t := range EmailTypes {
+		if t.String() == emailType {
+			return t, nil
+		}
+	}
+
+	return "", ErrBadRequest
+}
+
 func (e EmailType) String() string {
 	return string(e)
 }
+
+// SampleData returns the data to preview the email template with.
+func (e EmailType) SampleData() interface{} {
+	switch e {
+	case WelcomeEmail:
+		return WelcomeEmailData{Name: "Jane Doe"}
+	case ConfirmationEmail:
+		return ConfirmationEmailData{ConfirmationLink: "https://closi.app/confirm?code=sample"}
+	case BountyAwardedEmail, BountyReceivedEmail, BountyRefundedEmail, BountyExpiringEmail:
+		return BountyEmailData{Name: "Jane Doe", QuestionTitle: "How do I center a div?", Points: 50}
+	case NewAnswerEmail, AnswerAcceptedEmail:
+		return AnswerEmailData{Name: "Jane Doe", ActorName: "John Smith", QuestionTitle: "How do I center a div?", Points: 50}
+	case DailyDigestEmail, WeeklyDigestEmail:
+		return DigestEmailData{
+			Name:   "Jane Doe",
+			Weekly: e == WeeklyDigestEmail,
+			Questions: []Question{
+				{Title: "How do I center a div?"},
+				{Title: "What is the difference between a process and a thread?"},
+			},
+			Answers:         3,
+			Likes:           12,
+			AcceptedAnswers: 1,
+			PointsChange:    40,
+			Points:          240,
+		}
+	default:
+		return nil
+	}
+}
+
+// Category returns the category of a notification email, false is returned for the emails which are always sent.
+func (e EmailType) Category() (EmailCategory, bool) {
+	switch e {
+	case NewAnswerEmail:
+		return AnswersEmailCategory, true
+	case AnswerAcceptedEmail:
+		return AcceptedAnswersEmailCategory, true
+	case BountyAwardedEmail, BountyReceivedEmail, BountyRefundedEmail, BountyExpiringEmail:
+		return BountiesEmailCategory, true
+	case DailyDigestEmail, WeeklyDigestEmail:
+		return DigestsEmailCategory, true
+	default:
+		return "", false
+	}
+}
+
+// IsEmpty reports whether there is nothing to tell in the digest.
+func (d DigestEmailData) IsEmpty() bool {
+	return len(d.Questions) == 0 && d.Answers == 0 && d.Likes == 0 && d.AcceptedAnswers == 0 && d.PointsChange == 0
+}
+
+// UnsubscribeValue is signed into the unsubscribe links of the notification emails.
+func UnsubscribeValue(userID bson.ObjectID, category EmailCategory) string {
+	return userID.Hex() + ":" + string(category)
+}
+
+func ParseUnsubscribeValue(value string) (bson.ObjectID, EmailCategory, error) {
+	id, category, ok := strings.Cut(value, ":")
+	if !ok {
+		return bson.ObjectID{}, "", ErrEmailInvalidUnsubscribeToken
+	}
+
+	userID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return bson.ObjectID{}, "", ErrEmailInvalidUnsubscribeToken
+	}
+
+	switch EmailCategory(category) {
+	case AnswersEmailCategory, AcceptedAnswersEmailCategory, BountiesEmailCategory, DigestsEmailCategory:
+		return userID, EmailCategory(category), nil
+	default:
+		return bson.ObjectID{}, "", ErrEmailInvalidUnsubscribeToken
+	}
+}
+
+// UnsubscribeInput returns the email preferences update which turns off the category.
+func UnsubscribeInput(category EmailCategory) EmailPreferencesUpdateInput {
+	off := false
+
+	switch category {
+	case AnswersEmailCategory:
+		return EmailPreferencesUpdateInput{Answers: &off}
+	case AcceptedAnswersEmailCategory:
+		return EmailPreferencesUpdateInput{AcceptedAnswers: &off}
+	case BountiesEmailCategory:
+		return EmailPreferencesUpdateInput{Bounties: &off}
+	case DigestsEmailCategory:
+		digest := NoDigest
+		return EmailPreferencesUpdateInput{Digest: &digest}
+	default:
+		return EmailPreferencesUpdateInput{}
+	}
+}
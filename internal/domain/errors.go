@@ -6,6 +6,9 @@ var (
 	ErrInternalServerError = NewError("ERR_INTERNAL_SERVER_ERROR", "internal server error")
 	ErrBadRequest          = NewError("ERR_BAD_REQUEST", "bad request")
 	ErrUnauthorized        = NewError("ERR_UNAUTHORIZED", "unauthorized access")
+	ErrForbidden           = NewError("ERR_FORBIDDEN", "access forbidden")
+
+	ErrRestoreWindowExpired = NewError("ERR_RESTORE_WINDOW_EXPIRED", "restore window has expired")
 )
 
 type Error struct {
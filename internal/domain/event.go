@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"time"
+)
+
+const (
+	AnswerCreatedEvent  EventType = "answer_created"
+	AnswerLikedEvent    EventType = "answer_liked"
+	AnswerUnlikedEvent  EventType = "answer_unliked"
+	AnswerAcceptedEvent EventType = "answer_accepted"
+	ReferralJoinedEvent EventType = "referral_joined"
+	PointsChangedEvent  EventType = "points_changed"
+
+	EmailUndeliverableEvent EventType = "email_undeliverable"
+
+	NotificationCreatedEvent EventType = "notification_created"
+)
+
+type EventType string
+
+func (e EventType) String() string {
+	return string(e)
+}
+
+// Event is something that happened to the user content or account, UserID is the user concerned by the event
+// and ActorID is the user who caused it, if any.
+type Event struct {
+	Type           EventType      `json:"type"`
+	UserID         bson.ObjectID  `json:"user_id"`
+	ActorID        *bson.ObjectID `json:"actor_id,omitempty"`
+	QuestionID     *bson.ObjectID `json:"question_id,omitempty"`
+	AnswerID       *bson.ObjectID `json:"answer_id,omitempty"`
+	NotificationID *bson.ObjectID `json:"notification_id,omitempty"`
+	Points         int            `json:"points,omitempty"`
+	Balance        *uint          `json:"balance,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
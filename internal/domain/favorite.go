@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"time"
+)
+
+const (
+	FavoriteCollectionName = "favorites"
+
+	FavoriteDefaultLimit = 20
+	FavoriteMaxLimit     = 100
+	FavoriteMaxPage      = 10000
+)
+
+type Favorite struct {
+	ID         bson.ObjectID `bson:"_id" json:"id"`
+	UserID     bson.ObjectID `bson:"user_id" json:"user_id"`
+	QuestionID bson.ObjectID `bson:"question_id" json:"question_id"`
+	CreatedAt  time.Time     `bson:"created_at" json:"created_at"`
+}
+
+// FavoriteQuestion is a favorite question with the time it was added to favorites.
+type FavoriteQuestion struct {
+	Question    `bson:",inline"`
+	FavoritedAt time.Time `bson:"favorited_at" json:"favorited_at"`
+}
+
+type FavoriteGetAllFilter struct {
+	UserID bson.ObjectID
+	Page   int
+	Limit  int
+}
+
+// Skip returns the number of favorites before the filter page, pages start with 1.
+func (f FavoriteGetAllFilter) Skip() int {
+	if f.Page <= 1 {
+		return 0
+	}
+
+	return (f.Page - 1) * f.Limit
+}
+
+// ClampFavoriteLimit returns FavoriteDefaultLimit for non-positive limits and caps the rest at FavoriteMaxLimit.
+func ClampFavoriteLimit(limit int) int {
+	if limit <= 0 {
+		return FavoriteDefaultLimit
+	}
+
+	return min(limit, FavoriteMaxLimit)
+}
+
+// ClampFavoritePage returns 1 for non-positive pages and caps the rest at FavoriteMaxPage.
+func ClampFavoritePage(page int) int {
+	if page <= 0 {
+		return 1
+	}
+
+	return min(page, FavoriteMaxPage)
+}
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrFeedInvalidCursor = NewError("ERR_FEED_INVALID_CURSOR", "invalid feed cursor")
+)
+
+const (
+	QuestionFeedItem FeedItemType = "question"
+	AnswerFeedItem   FeedItemType = "answer"
+
+	FeedDefaultLimit = 20
+	FeedMaxLimit     = 50
+)
+
+type FeedItemType string
+
+// FeedItem is either a new question in followed tags or from followed users, or a new answer on a followed question.
+type FeedItem struct {
+	Type      FeedItemType `json:"type"`
+	Question  *Question    `json:"question,omitempty"`
+	Answer    *Answer      `json:"answer,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+func (i FeedItem) Cursor() FeedCursor {
+	if i.Answer != nil {
+		return FeedCursor{CreatedAt: i.Answer.CreatedAt, ID: i.Answer.ID}
+	}
+
+	return FeedCursor{CreatedAt: i.Question.CreatedAt, ID: i.Question.ID}
+}
+
+type Feed struct {
+	Items []FeedItem `json:"items"`
+	// NextCursor is empty when there are no more items
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// FeedCursor points to the last item of a feed page, the next page starts with items created before it.
+type FeedCursor struct {
+	CreatedAt time.Time
+	ID        bson.ObjectID
+}
+
+// Compare orders cursors by creation time and then by id.
+func (c FeedCursor) Compare(other FeedCursor) int {
+	if n := c.CreatedAt.Compare(other.CreatedAt); n != 0 {
+		return n
+	}
+
+	return bytes.Compare(c.ID[:], other.ID[:])
+}
+
+func (c FeedCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString(
+		[]byte(fmt.Sprintf("%d:%s", c.CreatedAt.UnixMilli(), c.ID.Hex())),
+	)
+}
+
+func ParseFeedCursor(cursor string) (FeedCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return FeedCursor{}, ErrFeedInvalidCursor
+	}
+
+	millis, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return FeedCursor{}, ErrFeedInvalidCursor
+	}
+
+	createdAt, err := strconv.ParseInt(millis, 10, 64)
+	if err != nil {
+		return FeedCursor{}, ErrFeedInvalidCursor
+	}
+
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return FeedCursor{}, ErrFeedInvalidCursor
+	}
+
+	return FeedCursor{CreatedAt: time.UnixMilli(createdAt), ID: objectID}, nil
+}
+
+// FeedFilter selects feed items created before the cursor, questions are matched by tags and authors,
+// answers by questions. Content of the excluded user is never selected.
+type FeedFilter struct {
+	TagIDs        []bson.ObjectID
+	UserIDs       []bson.ObjectID
+	QuestionIDs   []bson.ObjectID
+	ExcludeUserID bson.ObjectID
+	Before        *FeedCursor
+	Limit         int
+}
+
+// ClampFeedLimit returns FeedDefaultLimit for non-positive limits and caps the rest at FeedMaxLimit.
+func ClampFeedLimit(limit int) int {
+	if limit <= 0 {
+		return FeedDefaultLimit
+	}
+
+	return min(limit, FeedMaxLimit)
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"time"
+)
+
+var (
+	ErrFollowInvalidTarget = NewError("ERR_FOLLOW_INVALID_TARGET", "invalid follow target")
+	ErrFollowSelf          = NewError("ERR_FOLLOW_SELF", "you can't follow yourself")
+)
+
+const (
+	FollowCollectionName = "follows"
+
+	UserFollow     FollowTarget = "user"
+	TagFollow      FollowTarget = "tag"
+	QuestionFollow FollowTarget = "question"
+)
+
+// FollowTarget describes the kind of entity a user follows to get its updates in the feed.
+type FollowTarget string
+
+func ParseFollowTarget(target string) (FollowTarget, error) {
+	switch target {
+	case "user":
+		return UserFollow, nil
+	case "tag":
+		return TagFollow, nil
+	case "question":
+		return QuestionFollow, nil
+	default:
+		return "", ErrFollowInvalidTarget
+	}
+}
+
+type Follow struct {
+	ID         bson.ObjectID `bson:"_id" json:"id"`
+	UserID     bson.ObjectID `bson:"user_id" json:"user_id"`
+	TargetType FollowTarget  `bson:"target_type" json:"target_type"`
+	TargetID   bson.ObjectID `bson:"target_id" json:"target_id"`
+	CreatedAt  time.Time     `bson:"created_at" json:"created_at"`
+}
@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"time"
+)
+
+const AnswerLikeCollectionName = "answer_likes"
+
+type AnswerLike struct {
+	ID        bson.ObjectID `bson:"_id" json:"id"`
+	AnswerID  bson.ObjectID `bson:"answer_id" json:"answer_id"`
+	UserID    bson.ObjectID `bson:"user_id" json:"user_id"`
+	CreatedAt time.Time     `bson:"created_at" json:"created_at"`
+}
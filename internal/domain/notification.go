@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"time"
+)
+
+var (
+	ErrNotificationNotFound = NewError("ERR_NOTIFICATION_NOT_FOUND", "notification not found")
+)
+
+const (
+	NotificationCollectionName = "notifications"
+
+	NotificationDefaultLimit = 20
+	NotificationMaxLimit     = 100
+)
+
+// Notification is an inbox record created from an event, its text is rendered in the recipient language on read.
+type Notification struct {
+	ID         bson.ObjectID      `bson:"_id" json:"id"`
+	UserID     bson.ObjectID      `bson:"user_id" json:"user_id"`
+	Type       EventType          `bson:"type" json:"type"`
+	ActorID    *bson.ObjectID     `bson:"actor_id,omitempty" json:"actor_id,omitempty"`
+	QuestionID *bson.ObjectID     `bson:"question_id,omitempty" json:"question_id,omitempty"`
+	AnswerID   *bson.ObjectID     `bson:"answer_id,omitempty" json:"answer_id,omitempty"`
+	Params     NotificationParams `bson:"params" json:"-"`
+	IsRead     bool               `bson:"is_read" json:"is_read"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// NotificationParams is a snapshot of the data used to render the notification text.
+type NotificationParams struct {
+	ActorName     string `bson:"actor_name,omitempty"`
+	QuestionTitle string `bson:"question_title,omitempty"`
+	Points        int    `bson:"points,omitempty"`
+}
+
+type NotificationGetAllFilter struct {
+	UserID     bson.ObjectID
+	UnreadOnly bool
+	Page       int
+	Limit      int
+}
+
+// Skip returns the number of notifications before the filter page, pages start with 1.
+func (f NotificationGetAllFilter) Skip() int {
+	if f.Page <= 1 {
+		return 0
+	}
+
+	return (f.Page - 1) * f.Limit
+}
+
+// ClampNotificationLimit returns NotificationDefaultLimit for non-positive limits and caps the rest at NotificationMaxLimit.
+func ClampNotificationLimit(limit int) int {
+	if limit <= 0 {
+		return NotificationDefaultLimit
+	}
+
+	return min(limit, NotificationMaxLimit)
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"time"
+)
+
+var (
+	ErrEmailNotFound = NewError("ERR_EMAIL_NOT_FOUND", "email not found")
+)
+
+const (
+	EmailOutboxCollectionName = "email_outbox"
+
+	PendingEmailStatus EmailStatus = "pending"
+	SentEmailStatus    EmailStatus = "sent"
+	// FailedEmailStatus is set for the emails which weren't sent in EmailMaxAttempts, they are kept as dead letters.
+	FailedEmailStatus EmailStatus = "failed"
+
+	EmailMaxAttempts = 8
+	// EmailBaseBackoff is the delay after the first failed attempt, it doubles with every next one.
+	EmailBaseBackoff = time.Minute
+	EmailMaxBackoff  = 6 * time.Hour
+	// EmailDeliveryLease is how long a claimed email isn't picked up by other workers.
+	EmailDeliveryLease = 5 * time.Minute
+	// EmailDeliveryBatchSize is the amount of emails sent by a single run of the delivery job.
+	EmailDeliveryBatchSize = 100
+	// EmailSentRetention is how long sent emails are kept in the outbox.
+	EmailSentRetention = 30 * 24 * time.Hour
+
+	EmailDefaultLimit = 20
+	EmailMaxLimit     = 100
+)
+
+type EmailStatus string
+
+// OutboxEmail is a rendered email waiting to be sent or kept after sending.
+type OutboxEmail struct {
+	ID            bson.ObjectID       `bson:"_id" json:"id"`
+	To            string              `bson:"to" json:"to"`
+	Type          EmailType           `bson:"type" json:"type"`
+	Subject       string              `bson:"subject" json:"subject"`
+	Body          string              `bson:"body" json:"-"`
+	TextBody      string              `bson:"text_body" json:"-"`
+	Headers       map[string][]string `bson:"headers,omitempty" json:"-"`
+	Status        EmailStatus         `bson:"status" json:"status"`
+	Attempts      int                 `bson:"attempts" json:"attempts"`
+	NextAttemptAt time.Time           `bson:"next_attempt_at" json:"next_attempt_at"`
+	LastError     string              `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	CreatedAt     time.Time           `bson:"created_at" json:"created_at"`
+	SentAt        *time.Time          `bson:"sent_at,omitempty" json:"sent_at,omitempty"`
+}
+
+// EmailBackoff returns the delay before the next attempt to send an email which failed the given amount of times.
+func EmailBackoff(attempts int) time.Duration {
+	backoff := EmailBaseBackoff
+	for i := 1; i < attempts && backoff < EmailMaxBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, EmailMaxBackoff)
+}
+
+type EmailGetAllFilter struct {
+	Status EmailStatus
+	Page   int
+	Limit  int
+}
+
+// Skip returns the number of emails before the filter page, pages start with 1.
+func (f EmailGetAllFilter) Skip() int {
+	if f.Page <= 1 {
+		return 0
+	}
+
+	return (f.Page - 1) * f.Limit
+}
+
+// ClampEmailLimit returns EmailDefaultLimit for non-positive limits and caps the rest at EmailMaxLimit.
+func ClampEmailLimit(limit int) int {
+	if limit <= 0 {
+		return EmailDefaultLimit
+	}
+
+	return min(limit, EmailMaxLimit)
+}
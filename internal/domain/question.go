@@ -6,24 +6,70 @@ import (
 )
 
 var (
-	ErrQuestionNotFound = NewError("ERR_QUESTION_NOT_FOUND", "question not found")
+	ErrQuestionNotFound        = NewError("ERR_QUESTION_NOT_FOUND", "question not found")
+	ErrQuestionInvalidOriginal = NewError("ERR_QUESTION_INVALID_ORIGINAL", "question can't be a duplicate of this question")
+	ErrQuestionBountyClosed    = NewError("ERR_QUESTION_BOUNTY_CLOSED", "question bounty is already paid out or refunded")
 )
 
 const (
 	QuestionCollectionName = "questions"
+
+	NewestQuestionSort QuestionSort = "newest"
+	TopQuestionSort    QuestionSort = "top"
+	HotQuestionSort    QuestionSort = "hot"
+	ViewsQuestionSort  QuestionSort = "views"
+
+	// QuestionHotGravity defines how fast questions fall down in the "hot" sort.
+	QuestionHotGravity = 1.5
+
+	SimilarQuestionsLimit = 5
+	// QuestionSharedTagWeight is added to the text relevance of a similar question for every shared tag.
+	QuestionSharedTagWeight = 0.5
+
+	// DefaultViewDedupTTL is used when views.dedup_ttl isn't set, so a viewer is counted again after it.
+	DefaultViewDedupTTL = 24 * time.Hour
+	// QuestionViewersTTL is how long the unique viewers of a question are kept after its last view.
+	QuestionViewersTTL = 30 * 24 * time.Hour
+	// QuestionViewsFlushLockTTL bounds how long a views flush can hold the lock.
+	QuestionViewsFlushLockTTL = 5 * time.Minute
+
+	// DefaultBountyDuration is used when bounty.duration isn't set, so bounties don't expire right away.
+	DefaultBountyDuration = 7 * 24 * time.Hour
+	// BountyAutoAwardMinLikes is the minimum amount of likes an answer needs to be auto-awarded an expired bounty.
+	BountyAutoAwardMinLikes = 1
+	// BountyReminderWindow is how long before the bounty expiration the asker is reminded about it.
+	BountyReminderWindow = 24 * time.Hour
 )
 
 type Question struct {
-	ID             bson.ObjectID   `bson:"_id" json:"id"`
-	Title          string          `bson:"title" json:"title"`
-	Description    string          `bson:"description" json:"description"`
-	AttachmentsURL []string        `bson:"attachments_url" json:"attachments_url"`
-	Tags           []bson.ObjectID `bson:"tags" json:"tags"`
-	Points         uint            `bson:"points" json:"points"`
-	CountryID      bson.ObjectID   `bson:"country_id" json:"country_id"`
-	UserID         bson.ObjectID   `bson:"user_id" json:"user_id"`
-	CreatedAt      time.Time       `bson:"created_at" json:"created_at"`
-	UpdatedAt      time.Time       `bson:"updated_at" json:"updated_at"`
+	ID               bson.ObjectID   `bson:"_id" json:"id"`
+	Title            string          `bson:"title" json:"title"`
+	Description      string          `bson:"description" json:"description"`
+	AttachmentsURL   []string        `bson:"attachments_url" json:"attachments_url"`
+	Tags             []bson.ObjectID `bson:"tags" json:"tags"`
+	Points           uint            `bson:"points" json:"points"`
+	UpVotes          uint            `bson:"up_votes" json:"up_votes"`
+	DownVotes        uint            `bson:"down_votes" json:"down_votes"`
+	Score            int             `bson:"score" json:"score"`
+	Views            int             `bson:"views" json:"views"`
+	UniqueViewers    int             `bson:"unique_viewers" json:"unique_viewers"`
+	FavoritesCount   int             `bson:"favorites_count" json:"favorites_count"`
+	CountryID        bson.ObjectID   `bson:"country_id" json:"country_id"`
+	UserID           bson.ObjectID   `bson:"user_id" json:"user_id"`
+	AcceptedAnswerID *bson.ObjectID  `bson:"accepted_answer_id,omitempty" json:"accepted_answer_id,omitempty"`
+	DuplicateOf      *bson.ObjectID  `bson:"duplicate_of,omitempty" json:"duplicate_of,omitempty"`
+	BountyExpiresAt  *time.Time      `bson:"bounty_expires_at,omitempty" json:"bounty_expires_at,omitempty"`
+	BountyReleasedAt *time.Time      `bson:"bounty_released_at,omitempty" json:"bounty_released_at,omitempty"`
+	BountyRemindedAt *time.Time      `bson:"bounty_reminded_at,omitempty" json:"-"`
+	CreatedAt        time.Time       `bson:"created_at" json:"created_at"`
+	UpdatedAt        time.Time       `bson:"updated_at" json:"updated_at"`
+	DeletedAt        *time.Time      `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy        *bson.ObjectID  `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"`
+}
+
+// HasEscrow reports whether the question points are still held and not paid out or refunded.
+func (q Question) HasEscrow() bool {
+	return q.Points > 0 && q.AcceptedAnswerID == nil && q.BountyReleasedAt == nil
 }
 
 type QuestionGetAllFilter struct {
@@ -31,6 +77,28 @@ type QuestionGetAllFilter struct {
 	Tag       *bson.ObjectID
 	CountryID *bson.ObjectID
 	UserID    *bson.ObjectID
+	Sort      QuestionSort
+	Limit     int
+
+	// DeletedBefore selects only questions soft deleted before the time
+	DeletedBefore *time.Time
+}
+
+// QuestionViews are views of the question recorded since the last flush.
+type QuestionViews struct {
+	QuestionID    bson.ObjectID
+	Views         int
+	UniqueViewers int
+}
+
+// QuestionSimilarFilter selects questions matching the text or sharing the tags,
+// duplicates are never selected.
+type QuestionSimilarFilter struct {
+	Text      string
+	Tags      []bson.ObjectID
+	CountryID bson.ObjectID
+	ExcludeID *bson.ObjectID
+	Limit     int
 }
 
 type QuestionUpdateInput struct {
@@ -39,4 +107,21 @@ type QuestionUpdateInput struct {
 	AttachmentsURL []string
 	Tags           []bson.ObjectID
 	Points         *uint
+
+	BountyExpiresAt *time.Time
+}
+
+type QuestionSort string
+
+func ParseQuestionSort(sort string) QuestionSort {
+	switch sort {
+	case "top":
+		return TopQuestionSort
+	case "hot":
+		return HotQuestionSort
+	case "views":
+		return ViewsQuestionSort
+	default:
+		return NewestQuestionSort
+	}
 }
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	ErrRealtimeInvalidChannel = NewError("ERR_REALTIME_INVALID_CHANNEL", "invalid realtime channel")
+	ErrRealtimeInvalidEventID = NewError("ERR_REALTIME_INVALID_EVENT_ID", "invalid last event id")
+)
+
+var streamEventIDRegexp = regexp.MustCompile(`^\d+-\d+$`)
+
+const (
+	questionChannelPrefix      = "question:"
+	userChannelPrefix          = "user:"
+	notificationsChannelPrefix = "notifications:"
+	streamChannelPrefix        = "stream:"
+
+	// NotificationsChannel is the channel name clients use to subscribe to their own notifications.
+	NotificationsChannel = "notifications"
+
+	SubscribeRealtimeAction   RealtimeAction = "subscribe"
+	UnsubscribeRealtimeAction RealtimeAction = "unsubscribe"
+
+	// RealtimeBufferSize is the amount of messages kept for a slow client before new ones are dropped.
+	RealtimeBufferSize = 64
+
+	// EventStreamMaxLength is the approximate amount of recent events kept per user to resume event streams.
+	EventStreamMaxLength = 100
+	// EventStreamTTL is how long the recent events are kept after the last one.
+	EventStreamTTL = 24 * time.Hour
+)
+
+func QuestionChannel(id bson.ObjectID) string {
+	return questionChannelPrefix + id.Hex()
+}
+
+func UserChannel(id bson.ObjectID) string {
+	return userChannelPrefix + id.Hex()
+}
+
+func NotificationsChannelOf(userID bson.ObjectID) string {
+	return notificationsChannelPrefix + userID.Hex()
+}
+
+// StreamChannelOf returns the internal channel of the user event stream, clients can't subscribe to it directly.
+func StreamChannelOf(userID bson.ObjectID) string {
+	return streamChannelPrefix + userID.Hex()
+}
+
+// IsStreamedEvent reports whether the events of the type are kept in the user event stream.
+func IsStreamedEvent(eventType EventType) bool {
+	return eventType == NotificationCreatedEvent || eventType == PointsChangedEvent
+}
+
+func ValidateStreamEventID(id string) error {
+	if !streamEventIDRegexp.MatchString(id) {
+		return ErrRealtimeInvalidEventID
+	}
+
+	return nil
+}
+
+// ResolveRealtimeChannel validates the channel requested by the user and returns its internal name.
+// Question channels are public, user and notification channels are available only to their owner.
+func ResolveRealtimeChannel(channel string, userID bson.ObjectID) (string, error) {
+	if channel == NotificationsChannel {
+		return NotificationsChannelOf(userID), nil
+	}
+
+	if id, ok := strings.CutPrefix(channel, questionChannelPrefix); ok {
+		if _, err := bson.ObjectIDFromHex(id); err != nil {
+			return "", ErrRealtimeInvalidChannel
+		}
+		return channel, nil
+	}
+
+	if id, ok := strings.CutPrefix(channel, userChannelPrefix); ok {
+		objectID, err := bson.ObjectIDFromHex(id)
+		if err != nil {
+			return "", ErrRealtimeInvalidChannel
+		}
+		if objectID != userID {
+			return "", ErrForbidden
+		}
+		return channel, nil
+	}
+
+	return "", ErrRealtimeInvalidChannel
+}
+
+type RealtimeAction string
+
+// RealtimeCommand is sent by a client to manage its subscriptions.
+type RealtimeCommand struct {
+	Action  RealtimeAction `json:"action"`
+	Channel string         `json:"channel"`
+}
+
+// RealtimeMessage is an event delivered to the subscribers of the channel, ID is set for the event stream messages.
+type RealtimeMessage struct {
+	ID      string `json:"id,omitempty"`
+	Channel string `json:"channel"`
+	Event   Event  `json:"event"`
+}
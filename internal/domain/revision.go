@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrRevisionNotFound = NewError("ERR_REVISION_NOT_FOUND", "revision not found")
+)
+
+const (
+	RevisionCollectionName        = "revisions"
+	RevisionCounterCollectionName = "revision_counters"
+
+	RevisionTitleField          = "title"
+	RevisionDescriptionField    = "description"
+	RevisionAttachmentsURLField = "attachments_url"
+	RevisionTagsField           = "tags"
+	RevisionTextField           = "text"
+)
+
+type Revision struct {
+	ID            bson.ObjectID   `bson:"_id" json:"id"`
+	TargetType    TargetType      `bson:"target_type" json:"target_type"`
+	TargetID      bson.ObjectID   `bson:"target_id" json:"target_id"`
+	Number        int             `bson:"number" json:"number"`
+	UserID        bson.ObjectID   `bson:"user_id" json:"user_id"`
+	ChangedFields []string        `bson:"changed_fields" json:"changed_fields"`
+	Content       RevisionContent `bson:"content" json:"content"`
+	RollbackOf    *bson.ObjectID  `bson:"rollback_of,omitempty" json:"rollback_of,omitempty"`
+	CreatedAt     time.Time       `bson:"created_at" json:"created_at"`
+}
+
+// RevisionContent is a snapshot of the editable content right after the revision was made.
+type RevisionContent struct {
+	Title          string          `bson:"title,omitempty" json:"title,omitempty"`
+	Description    string          `bson:"description,omitempty" json:"description,omitempty"`
+	AttachmentsURL []string        `bson:"attachments_url,omitempty" json:"attachments_url,omitempty"`
+	Tags           []bson.ObjectID `bson:"tags,omitempty" json:"tags,omitempty"`
+	Text           string          `bson:"text,omitempty" json:"text,omitempty"`
+}
+
+func (c RevisionContent) ChangedFields(previous RevisionContent) []string {
+	var fields []string
+
+	if c.Title != previous.Title {
+		fields = append(fields, RevisionTitleField)
+	}
+	if c.Description != previous.Description {
+		fields = append(fields, RevisionDescriptionField)
+	}
+	if !slices.Equal(c.AttachmentsURL, previous.AttachmentsURL) {
+		fields = append(fields, RevisionAttachmentsURLField)
+	}
+	if !slices.Equal(c.Tags, previous.Tags) {
+		fields = append(fields, RevisionTagsField)
+	}
+	if c.Text != previous.Text {
+		fields = append(fields, RevisionTextField)
+	}
+
+	return fields
+}
+
+// Field returns the textual representation of the content field used for diffs.
+func (c RevisionContent) Field(field string) string {
+	switch field {
+	case RevisionTitleField:
+		return c.Title
+	case RevisionDescriptionField:
+		return c.Description
+	case RevisionAttachmentsURLField:
+		return strings.Join(c.AttachmentsURL, "\n")
+	case RevisionTagsField:
+		tags := make([]string, 0, len(c.Tags))
+		for _, tag := range c.Tags {
+			tags = append(tags, tag.Hex())
+		}
+		return strings.Join(tags, "\n")
+	case RevisionTextField:
+		return c.Text
+	default:
+		return ""
+	}
+}
+
+// QuestionUpdateInput returns the update restoring the question content, empty fields are restored as well.
+func (c RevisionContent) QuestionUpdateInput() QuestionUpdateInput {
+	input := QuestionUpdateInput{
+		Title:          &c.Title,
+		Description:    &c.Description,
+		AttachmentsURL: c.AttachmentsURL,
+		Tags:           c.Tags,
+	}
+
+	// nil slices mean the field isn't updated
+	if input.AttachmentsURL == nil {
+		input.AttachmentsURL = []string{}
+	}
+	if input.Tags == nil {
+		input.Tags = []bson.ObjectID{}
+	}
+
+	return input
+}
+
+func NewQuestionRevisionContent(question Question) RevisionContent {
+	return RevisionContent{
+		Title:          question.Title,
+		Description:    question.Description,
+		AttachmentsURL: question.AttachmentsURL,
+		Tags:           question.Tags,
+	}
+}
+
+func NewAnswerRevisionContent(answer Answer) RevisionContent {
+	return RevisionContent{
+		Text: answer.Text,
+	}
+}
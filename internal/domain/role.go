@@ -0,0 +1,20 @@
+package domain
+
+const (
+	UserRole      Role = "user"
+	ModeratorRole Role = "moderator"
+	AdminRole     Role = "admin"
+)
+
+type Role string
+
+func ParseRole(role string) Role {
+	switch role {
+	case "moderator":
+		return ModeratorRole
+	case "admin":
+		return AdminRole
+	default:
+		return UserRole
+	}
+}
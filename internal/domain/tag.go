@@ -2,18 +2,70 @@ package domain
 
 import (
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"golang.org/x/text/unicode/norm"
+	"strings"
+	"time"
+	"unicode"
 )
 
 var (
-	ErrTagNotFound = NewError("ERR_TAG_NOT_FOUND", "tag not found")
+	ErrTagNotFound      = NewError("ERR_TAG_NOT_FOUND", "tag not found")
+	ErrTagInvalidName   = NewError("ERR_TAG_INVALID_NAME", "invalid tag name")
+	ErrTagAlreadyExists = NewError("ERR_TAG_ALREADY_EXISTS", "tag with this name or synonym already exists")
 )
 
 const (
 	TagCollectionName = "tags"
+
+	TagDefaultLimit      = 10
+	TagMaxLimit          = 50
+	TagTopQuestionsLimit = 5
+
+	// DefaultTagTrendingWindow is used when tags.trending_window isn't set.
+	DefaultTagTrendingWindow = 7 * 24 * time.Hour
 )
 
 type Tag struct {
-	ID        bson.ObjectID `bson:"_id" json:"id"`
-	Name      string        `bson:"name" json:"name"`
-	CountryID bson.ObjectID `bson:"country_id" json:"country_id"`
+	ID           bson.ObjectID     `bson:"_id" json:"id"`
+	Name         string            `bson:"name" json:"name"`
+	CountryID    bson.ObjectID     `bson:"country_id" json:"country_id"`
+	UsageCount   int               `bson:"usage_count" json:"usage_count"`
+	Descriptions map[string]string `bson:"descriptions,omitempty" json:"descriptions,omitempty"`
+	Synonyms     []string          `bson:"synonyms,omitempty" json:"synonyms,omitempty"`
+}
+
+// TrendingTag is a tag with the number of questions which used it within the trending window.
+type TrendingTag struct {
+	Tag       `bson:",inline"`
+	Questions int `bson:"questions" json:"questions"`
+}
+
+// TagUpdateInput contains a new tag name and descriptions by language,
+// an empty description removes the description for the language.
+type TagUpdateInput struct {
+	Name         *string
+	Descriptions map[string]string
+}
+
+type TagTrendingFilter struct {
+	CountryID bson.ObjectID
+	Since     time.Time
+	Limit     int
+}
+
+// ClampTagLimit returns TagDefaultLimit for non-positive limits and caps the rest at TagMaxLimit.
+func ClampTagLimit(limit int) int {
+	if limit <= 0 {
+		return TagDefaultLimit
+	}
+
+	return min(limit, TagMaxLimit)
+}
+
+// NormalizeTagName brings tag name to its canonical form: unicode NFKC, lower case,
+// no leading and trailing spaces, inner whitespace collapsed into single "-".
+func NormalizeTagName(name string) string {
+	name = strings.ToLower(norm.NFKC.String(name))
+
+	return strings.Join(strings.FieldsFunc(name, unicode.IsSpace), "-")
 }
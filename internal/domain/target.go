@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+const (
+	QuestionTarget TargetType = "question"
+	AnswerTarget   TargetType = "answer"
+
+	// DefaultRestoreWindow is used when content.restore_window isn't set, so deleted content isn't purged right away.
+	DefaultRestoreWindow = 72 * time.Hour
+)
+
+// TargetType describes the kind of content other entities (comments, revisions, etc.) are attached to.
+type TargetType string
+
+func ParseTargetType(targetType string) (TargetType, error) {
+	switch targetType {
+	case "question":
+		return QuestionTarget, nil
+	case "answer":
+		return AnswerTarget, nil
+	default:
+		return "", ErrBadRequest
+	}
+}
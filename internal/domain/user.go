@@ -5,8 +5,6 @@ import (
 	"time"
 )
 
-// TODO: admins
-
 var (
 	ErrUserAlreadyExists      = NewError("ERR_USER_ALREADY_EXISTS", "user already exists")
 	ErrUserNotFound           = NewError("ERR_USER_NOT_FOUND", "user not found")
@@ -20,6 +18,8 @@ const (
 	UserReferralPoints = 50
 
 	UserReferralCodeLength = 4
+
+	UserDefaultTimezone = "UTC"
 )
 
 type User struct {
@@ -30,23 +30,103 @@ type User struct {
 	Password     string          `bson:"password" json:"password"`
 	AvatarURL    string          `bson:"avatar_url" json:"avatar_url"`
 	Points       uint            `bson:"points" json:"points"`
-	Favorites    []bson.ObjectID `bson:"favorites" json:"favorites"`
 	Achievements []bson.ObjectID `bson:"achievements" json:"achievements"`
 	ReferralCode string          `bson:"referral_code" json:"referral_code"`
 	Subscription Subscription    `bson:"subscription" json:"subscription"`
 	Settings     UserSettings    `bson:"settings" json:"settings"`
+	Role         Role            `bson:"role" json:"role"`
 	IsConfirmed  bool            `bson:"is_confirmed" json:"is_confirmed"`
 	IsBlocked    bool            `bson:"is_blocked" json:"is_blocked"`
+	Digest       UserDigest      `bson:"digest" json:"-"`
+	EmailBounce  *EmailBounce    `bson:"email_bounce,omitempty" json:"email_bounce,omitempty"`
 	CreatedAt    time.Time       `bson:"created_at" json:"created_at"`
 	UpdatedAt    time.Time       `bson:"updated_at" json:"updated_at"`
 	// TODO: achievements logic
 }
 
+func (u User) IsModerator() bool {
+	return u.Role == ModeratorRole || u.Role == AdminRole
+}
+
+func (u User) IsAdmin() bool {
+	return u.Role == AdminRole
+}
+
 type UserSettings struct {
-	CountryID          bson.ObjectID `bson:"country_id" json:"country_id"`
-	Language           string        `bson:"language" json:"language"`
-	Appearance         Appearance    `bson:"appearance" json:"appearance"`
-	EmailNotifications bool          `bson:"email_notifications" json:"email_notifications"`
+	CountryID        bson.ObjectID    `bson:"country_id" json:"country_id"`
+	Language         string           `bson:"language" json:"language"`
+	Appearance       Appearance       `bson:"appearance" json:"appearance"`
+	Timezone         string           `bson:"timezone" json:"timezone"`
+	EmailPreferences EmailPreferences `bson:"email_preferences" json:"email_preferences"`
+}
+
+// UnmarshalBSON gives the users who weren't migrated by split_email_notifications yet
+// their previous email notifications setting or the default email preferences, so their emails aren't suppressed.
+// Missing preferences are enabled as well.
+func (s *UserSettings) UnmarshalBSON(data []byte) error {
+	type userSettings UserSettings
+
+	decoded := userSettings{EmailPreferences: DefaultEmailPreferences()}
+	if err := bson.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*s = UserSettings(decoded)
+
+	raw := bson.Raw(data)
+	if _, err := raw.LookupErr("email_preferences"); err == nil {
+		return nil
+	}
+
+	if enabled, ok := raw.Lookup("email_notifications").BooleanOK(); ok {
+		s.EmailPreferences.Answers = enabled
+		s.EmailPreferences.AcceptedAnswers = enabled
+		s.EmailPreferences.Bounties = enabled
+	}
+
+	return nil
+}
+
+// Location returns the user time zone, UTC is used if it's not set.
+func (s UserSettings) Location() *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return loc
+}
+
+// EmailPreferences tells which categories of notification emails the user receives.
+type EmailPreferences struct {
+	Answers         bool            `bson:"answers" json:"answers"`
+	AcceptedAnswers bool            `bson:"accepted_answers" json:"accepted_answers"`
+	Bounties        bool            `bson:"bounties" json:"bounties"`
+	Digest          DigestFrequency `bson:"digest" json:"digest"`
+}
+
+func DefaultEmailPreferences() EmailPreferences {
+	return EmailPreferences{
+		Answers:         true,
+		AcceptedAnswers: true,
+		Bounties:        true,
+		Digest:          NoDigest,
+	}
+}
+
+func (p EmailPreferences) Allows(category EmailCategory) bool {
+	switch category {
+	case AnswersEmailCategory:
+		return p.Answers
+	case AcceptedAnswersEmailCategory:
+		return p.AcceptedAnswers
+	case BountiesEmailCategory:
+		return p.Bounties
+	case DigestsEmailCategory:
+		return p.Digest.IsEnabled()
+	default:
+		return false
+	}
 }
 
 type UserUpdateInput struct {
@@ -58,8 +138,16 @@ type UserUpdateInput struct {
 }
 
 type UserSettingsUpdateInput struct {
-	CountryID          *bson.ObjectID
-	Language           *string
-	Appearance         *Appearance
-	EmailNotifications *bool
+	CountryID        *bson.ObjectID
+	Language         *string
+	Appearance       *Appearance
+	Timezone         *string
+	EmailPreferences *EmailPreferencesUpdateInput
+}
+
+type EmailPreferencesUpdateInput struct {
+	Answers         *bool
+	AcceptedAnswers *bool
+	Bounties        *bool
+	Digest          *DigestFrequency
 }
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"time"
+)
+
+var (
+	ErrVoteInvalidValue = NewError("ERR_VOTE_INVALID_VALUE", "invalid vote value")
+	ErrVoteOwnQuestion  = NewError("ERR_VOTE_OWN_QUESTION", "you can't vote for your own question")
+)
+
+const (
+	QuestionVoteCollectionName = "question_votes"
+
+	UpVote   VoteValue = 1
+	DownVote VoteValue = -1
+
+	QuestionUpVoteReputation   = 2
+	QuestionDownVoteReputation = -1
+)
+
+type VoteValue int
+
+func ParseVoteValue(value int) (VoteValue, error) {
+	switch VoteValue(value) {
+	case UpVote:
+		return UpVote, nil
+	case DownVote:
+		return DownVote, nil
+	default:
+		return 0, ErrVoteInvalidValue
+	}
+}
+
+// Reputation returns the amount of points the question author gets for the vote.
+func (v VoteValue) Reputation() int {
+	switch v {
+	case UpVote:
+		return QuestionUpVoteReputation
+	case DownVote:
+		return QuestionDownVoteReputation
+	default:
+		return 0
+	}
+}
+
+type QuestionVote struct {
+	ID         bson.ObjectID `bson:"_id" json:"id"`
+	QuestionID bson.ObjectID `bson:"question_id" json:"question_id"`
+	UserID     bson.ObjectID `bson:"user_id" json:"user_id"`
+	Value      VoteValue     `bson:"value" json:"value"`
+	Reputation *int          `bson:"reputation,omitempty" json:"-"`
+	CreatedAt  time.Time     `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time     `bson:"updated_at" json:"updated_at"`
+}
+
+// AppliedReputation returns the reputation the vote actually gave the question author,
+// votes stored before it was recorded are assumed to give the full reputation of their value.
+func (v QuestionVote) AppliedReputation() int {
+	if v.Reputation == nil {
+		return v.Value.Reputation()
+	}
+
+	return *v.Reputation
+}
@@ -0,0 +1,51 @@
+package migration
+
+import (
+	"context"
+	"github.com/Closi-App/backend/internal/domain"
+	"github.com/Closi-App/backend/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// splitEmailNotifications replaces the single email notifications setting of the users
+// with the per category email preferences, every category gets the previous value.
+func splitEmailNotifications(ctx context.Context, db *mongo.Database, log *logger.Logger) error {
+	var migrated int64
+
+	for _, enabled := range []bool{true, false} {
+		res, err := db.Collection(domain.UserCollectionName).
+			UpdateMany(ctx,
+				bson.M{"settings.email_notifications": enabled},
+				bson.M{
+					"$set": bson.M{"settings.email_preferences": domain.EmailPreferences{
+						Answers:         enabled,
+						AcceptedAnswers: enabled,
+						Bounties:        enabled,
+					}},
+					"$unset": bson.M{"settings.email_notifications": ""},
+				},
+			)
+		if err != nil {
+			return err
+		}
+
+		migrated += res.ModifiedCount
+	}
+
+	// users who never had the setting get the defaults
+	res, err := db.Collection(domain.UserCollectionName).
+		UpdateMany(ctx,
+			bson.M{"settings.email_preferences": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"settings.email_preferences": domain.DefaultEmailPreferences()}},
+		)
+	if err != nil {
+		return err
+	}
+
+	migrated += res.ModifiedCount
+
+	log.Info().Msgf("migrated email notifications settings of %d users", migrated)
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package migration
+
+import (
+	"context"
+	"github.com/Closi-App/backend/internal/domain"
+	"github.com/Closi-App/backend/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"time"
+)
+
+// moveFavorites moves question ids from the users favorites arrays into the favorites collection
+// and counts favorites of every question.
+func moveFavorites(ctx context.Context, db *mongo.Database, log *logger.Logger) error {
+	cursor, err := db.Collection(domain.UserCollectionName).
+		Find(ctx, bson.M{"favorites.0": bson.M{"$exists": true}},
+			options.Find().SetProjection(bson.M{"favorites": 1}))
+	if err != nil {
+		return err
+	}
+
+	var users []struct {
+		ID        bson.ObjectID   `bson:"_id"`
+		Favorites []bson.ObjectID `bson:"favorites"`
+	}
+
+	if err = cursor.All(ctx, &users); err != nil {
+		return err
+	}
+
+	// the time the question was added to favorites wasn't stored, so the migration time is used
+	now := time.Now()
+
+	var moved int
+
+	for _, user := range users {
+		for _, questionID := range user.Favorites {
+			res, err := db.Collection(domain.FavoriteCollectionName).
+				UpdateOne(ctx,
+					bson.M{"user_id": user.ID, "question_id": questionID},
+					bson.M{"$setOnInsert": bson.M{"_id": bson.NewObjectID(), "created_at": now}},
+					options.Update().SetUpsert(true),
+				)
+			if err != nil {
+				return err
+			}
+
+			moved += int(res.UpsertedCount)
+		}
+	}
+
+	if err = countFavorites(ctx, db); err != nil {
+		return err
+	}
+
+	if _, err = db.Collection(domain.UserCollectionName).
+		UpdateMany(ctx, bson.M{"favorites": bson.M{"$exists": true}}, bson.M{"$unset": bson.M{"favorites": ""}}); err != nil {
+		return err
+	}
+
+	log.Info().Msgf("moved %d favorites of %d users", moved, len(users))
+
+	return nil
+}
+
+func countFavorites(ctx context.Context, db *mongo.Database) error {
+	cursor, err := db.Collection(domain.FavoriteCollectionName).
+		Aggregate(ctx, mongo.Pipeline{
+			{{Key: "$group", Value: bson.M{"_id": "$question_id", "count": bson.M{"$sum": 1}}}},
+		})
+	if err != nil {
+		return err
+	}
+
+	var counts []struct {
+		ID    bson.ObjectID `bson:"_id"`
+		Count int           `bson:"count"`
+	}
+
+	if err = cursor.All(ctx, &counts); err != nil {
+		return err
+	}
+
+	if _, err = db.Collection(domain.QuestionCollectionName).
+		UpdateMany(ctx, bson.M{}, bson.M{"$set": bson.M{"favorites_count": 0}}); err != nil {
+		return err
+	}
+
+	for _, c := range counts {
+		if _, err = db.Collection(domain.QuestionCollectionName).
+			UpdateOne(ctx, bson.M{"_id": c.ID}, bson.M{"$set": bson.M{"favorites_count": c.Count}}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"github.com/Closi-App/backend/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// Migration is a one-off data migration, it must be safe to run more than once.
+type Migration func(ctx context.Context, db *mongo.Database, log *logger.Logger) error
+
+var migrations = map[string]Migration{
+	"normalize_tags":            normalizeTags,
+	"count_tag_usage":           countTagUsage,
+	"move_favorites":            moveFavorites,
+	"split_email_notifications": splitEmailNotifications,
+}
+
+func Run(ctx context.Context, db *mongo.Database, log *logger.Logger, name string) error {
+	migration, ok := migrations[name]
+	if !ok {
+		return fmt.Errorf("unknown migration: %q", name)
+	}
+
+	log.Info().Msgf("running migration %s", name)
+
+	if err := migration(ctx, db, log); err != nil {
+		return fmt.Errorf("error running migration %s: %w", name, err)
+	}
+
+	log.Info().Msgf("migration %s is done", name)
+
+	return nil
+}
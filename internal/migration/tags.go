@@ -0,0 +1,140 @@
+package migration
+
+import (
+	"context"
+	"github.com/Closi-App/backend/internal/domain"
+	"github.com/Closi-App/backend/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// normalizeTags merges tags which have the same normalized name within a country into the oldest one,
+// rewrites references to the merged tags and renames the remaining tags to their normalized names.
+func normalizeTags(ctx context.Context, db *mongo.Database, log *logger.Logger) error {
+	cursor, err := db.Collection(domain.TagCollectionName).
+		Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return err
+	}
+
+	var tags []domain.Tag
+
+	if err = cursor.All(ctx, &tags); err != nil {
+		return err
+	}
+
+	type tagKey struct {
+		countryID bson.ObjectID
+		name      string
+	}
+
+	canonical := make(map[tagKey]domain.Tag)
+	duplicates := make(map[bson.ObjectID][]bson.ObjectID)
+
+	var order []tagKey
+
+	for _, tag := range tags {
+		name := domain.NormalizeTagName(tag.Name)
+		if name == "" {
+			log.Warn().Msgf("skipping tag (%s) with empty normalized name", tag.ID.Hex())
+			continue
+		}
+
+		key := tagKey{countryID: tag.CountryID, name: name}
+
+		if c, ok := canonical[key]; ok {
+			duplicates[c.ID] = append(duplicates[c.ID], tag.ID)
+			continue
+		}
+
+		canonical[key] = tag
+		order = append(order, key)
+	}
+
+	for _, key := range order {
+		tag := canonical[key]
+
+		if ids := duplicates[tag.ID]; len(ids) > 0 {
+			if err = mergeTags(ctx, db, tag.ID, ids); err != nil {
+				return err
+			}
+
+			log.Info().Msgf("merged %d duplicates into tag %q (%s)", len(ids), key.name, tag.ID.Hex())
+		}
+
+		if tag.Name != key.name {
+			if _, err = db.Collection(domain.TagCollectionName).
+				UpdateOne(ctx, bson.M{"_id": tag.ID}, bson.M{"$set": bson.M{"name": key.name}}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// mergeTags replaces references to duplicate tags with the canonical one and deletes the duplicates.
+func mergeTags(ctx context.Context, db *mongo.Database, canonicalID bson.ObjectID, duplicateIDs []bson.ObjectID) error {
+	references := map[string]string{
+		domain.QuestionCollectionName: "tags",
+		domain.RevisionCollectionName: "content.tags",
+	}
+
+	for collection, field := range references {
+		filter := bson.M{field: bson.M{"$in": duplicateIDs}}
+
+		if _, err := db.Collection(collection).
+			UpdateMany(ctx, filter, bson.M{"$addToSet": bson.M{field: canonicalID}}); err != nil {
+			return err
+		}
+
+		if _, err := db.Collection(collection).
+			UpdateMany(ctx, filter, bson.M{"$pull": bson.M{field: bson.M{"$in": duplicateIDs}}}); err != nil {
+			return err
+		}
+	}
+
+	_, err := db.Collection(domain.TagCollectionName).
+		DeleteMany(ctx, bson.M{"_id": bson.M{"$in": duplicateIDs}})
+
+	return err
+}
+
+// countTagUsage recalculates usage counters of all tags from the questions which aren't deleted.
+func countTagUsage(ctx context.Context, db *mongo.Database, log *logger.Logger) error {
+	cursor, err := db.Collection(domain.QuestionCollectionName).
+		Aggregate(ctx, mongo.Pipeline{
+			{{Key: "$match", Value: bson.M{"deleted_at": bson.M{"$exists": false}}}},
+			{{Key: "$unwind", Value: "$tags"}},
+			{{Key: "$group", Value: bson.M{"_id": "$tags", "count": bson.M{"$sum": 1}}}},
+		})
+	if err != nil {
+		return err
+	}
+
+	var counts []struct {
+		ID    bson.ObjectID `bson:"_id"`
+		Count int           `bson:"count"`
+	}
+
+	if err = cursor.All(ctx, &counts); err != nil {
+		return err
+	}
+
+	if _, err = db.Collection(domain.TagCollectionName).
+		UpdateMany(ctx, bson.M{}, bson.M{"$set": bson.M{"usage_count": 0}}); err != nil {
+		return err
+	}
+
+	for _, c := range counts {
+		if _, err = db.Collection(domain.TagCollectionName).
+			UpdateOne(ctx, bson.M{"_id": c.ID}, bson.M{"$set": bson.M{"usage_count": c.Count}}); err != nil {
+			return err
+		}
+	}
+
+	log.Info().Msgf("updated usage counters of %d tags", len(counts))
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/Closi-App/backend/internal/domain"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 	"time"
 )
 
@@ -13,12 +14,18 @@ type AnswerRepository interface {
 	Create(ctx context.Context, answer domain.Answer) error
 	GetAll(ctx context.Context, filter ...domain.AnswerGetAllFilter) ([]domain.Answer, error)
 	GetByID(ctx context.Context, id bson.ObjectID) (domain.Answer, error)
-	Update(ctx context.Context, id, userID bson.ObjectID, input domain.AnswerUpdateInput) error
-	Delete(ctx context.Context, id, userID bson.ObjectID) error
+	GetMostLiked(ctx context.Context, questionID, excludeUserID bson.ObjectID, minLikes int) (domain.Answer, error)
+	GetFeed(ctx context.Context, filter domain.FeedFilter) ([]domain.Answer, error)
+	Update(ctx context.Context, id bson.ObjectID, input domain.AnswerUpdateInput) error
+	Delete(ctx context.Context, id, deletedBy bson.ObjectID) error
 
 	AddLike(ctx context.Context, id bson.ObjectID) error
 	RemoveLike(ctx context.Context, id bson.ObjectID) error
 	Verify(ctx context.Context, id bson.ObjectID) error
+
+	GetDeletedByID(ctx context.Context, id bson.ObjectID) (domain.Answer, error)
+	Restore(ctx context.Context, id bson.ObjectID) error
+	Purge(ctx context.Context, id bson.ObjectID) error
 }
 
 type answerRepository struct {
@@ -38,22 +45,61 @@ func (r *answerRepository) Create(ctx context.Context, answer domain.Answer) err
 	return err
 }
 
+// GetAll returns answers which aren't deleted, answers of the deleted questions are excluded as well
+// unless deleted answers are selected.
 func (r *answerRepository) GetAll(ctx context.Context, filter ...domain.AnswerGetAllFilter) ([]domain.Answer, error) {
-	filterFields := bson.M{}
-
+	var f domain.AnswerGetAllFilter
 	if len(filter) > 0 {
-		f := filter[0]
+		f = filter[0]
+	}
+
+	filterFields := bson.M{"deleted_at": notDeletedFilter}
+
+	if f.QuestionID != nil {
+		filterFields["question_id"] = f.QuestionID
+	}
+	if f.UserID != nil {
+		filterFields["user_id"] = f.UserID
+	}
+
+	var questionStages mongo.Pipeline
+
+	switch {
+	case f.DeletedBefore != nil:
+		filterFields["deleted_at"] = bson.M{"$lte": f.DeletedBefore}
+	case f.IncludeDeleted:
+		delete(filterFields, "deleted_at")
+	case f.QuestionID != nil:
+		count, err := r.db.Collection(domain.QuestionCollectionName).
+			CountDocuments(ctx, bson.M{"_id": f.QuestionID, "deleted_at": bson.M{"$exists": true}}, options.Count().SetLimit(1))
+		if err != nil {
+			return nil, err
+		}
 
-		if f.QuestionID != nil {
-			filterFields["question_id"] = f.QuestionID
+		if count > 0 {
+			return []domain.Answer{}, nil
 		}
-		if f.UserID != nil {
-			filterFields["user_id"] = f.UserID
+	default:
+		questionStages = mongo.Pipeline{
+			{{Key: "$lookup", Value: bson.M{
+				"from": domain.QuestionCollectionName,
+				"let":  bson.M{"question_id": "$question_id"},
+				"pipeline": bson.A{
+					bson.M{"$match": bson.M{
+						"$expr":      bson.M{"$eq": bson.A{"$_id", "$$question_id"}},
+						"deleted_at": bson.M{"$exists": true},
+					}},
+					bson.M{"$project": bson.M{"_id": 1}},
+				},
+				"as": "deleted_question",
+			}}},
+			{{Key: "$match", Value: bson.M{"deleted_question": bson.M{"$size": 0}}}},
+			{{Key: "$project", Value: bson.M{"deleted_question": 0}}},
 		}
 	}
 
 	cursor, err := r.db.Collection(domain.AnswerCollectionName).
-		Find(ctx, filterFields)
+		Aggregate(ctx, append(mongo.Pipeline{{{Key: "$match", Value: filterFields}}}, questionStages...))
 	if err != nil {
 		return nil, err
 	}
@@ -71,7 +117,7 @@ func (r *answerRepository) GetByID(ctx context.Context, id bson.ObjectID) (domai
 	var answer domain.Answer
 
 	err := r.db.Collection(domain.AnswerCollectionName).
-		FindOne(ctx, bson.M{"_id": id}).Decode(&answer)
+		FindOne(ctx, bson.M{"_id": id, "deleted_at": notDeletedFilter}).Decode(&answer)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return domain.Answer{}, domain.ErrAnswerNotFound
@@ -83,7 +129,62 @@ func (r *answerRepository) GetByID(ctx context.Context, id bson.ObjectID) (domai
 	return answer, nil
 }
 
-func (r *answerRepository) Update(ctx context.Context, id, userID bson.ObjectID, input domain.AnswerUpdateInput) error {
+// GetMostLiked returns the earliest of the most liked answers to the question having at least minLikes likes,
+// answers of the excluded user aren't selected.
+func (r *answerRepository) GetMostLiked(ctx context.Context, questionID, excludeUserID bson.ObjectID, minLikes int) (domain.Answer, error) {
+	var answer domain.Answer
+
+	err := r.db.Collection(domain.AnswerCollectionName).
+		FindOne(ctx,
+			bson.M{
+				"question_id": questionID,
+				"user_id":     bson.M{"$ne": excludeUserID},
+				"likes":       bson.M{"$gte": minLikes},
+				"deleted_at":  notDeletedFilter,
+			},
+			options.FindOne().SetSort(bson.D{{Key: "likes", Value: -1}, {Key: "created_at", Value: 1}}),
+		).Decode(&answer)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.Answer{}, domain.ErrAnswerNotFound
+		}
+
+		return domain.Answer{}, err
+	}
+
+	return answer, nil
+}
+
+func (r *answerRepository) GetFeed(ctx context.Context, filter domain.FeedFilter) ([]domain.Answer, error) {
+	if len(filter.QuestionIDs) == 0 {
+		return nil, nil
+	}
+
+	match := bson.M{
+		"question_id": bson.M{"$in": filter.QuestionIDs},
+		"user_id":     bson.M{"$ne": filter.ExcludeUserID},
+		"deleted_at":  notDeletedFilter,
+		"$and":        bson.A{feedBeforeFilter(filter.Before)},
+	}
+
+	cursor, err := r.db.Collection(domain.AnswerCollectionName).
+		Find(ctx, match, options.Find().
+			SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+			SetLimit(int64(filter.Limit)))
+	if err != nil {
+		return nil, err
+	}
+
+	var answers []domain.Answer
+
+	if err = cursor.All(ctx, &answers); err != nil {
+		return nil, err
+	}
+
+	return answers, nil
+}
+
+func (r *answerRepository) Update(ctx context.Context, id bson.ObjectID, input domain.AnswerUpdateInput) error {
 	updateFields := bson.M{}
 
 	if input.Text != nil {
@@ -92,17 +193,34 @@ func (r *answerRepository) Update(ctx context.Context, id, userID bson.ObjectID,
 
 	updateFields["updated_at"] = time.Now()
 
-	_, err := r.db.Collection(domain.AnswerCollectionName).
-		UpdateOne(ctx, bson.M{"_id": id, "user_id": userID}, bson.M{"$set": updateFields})
+	res, err := r.db.Collection(domain.AnswerCollectionName).
+		UpdateOne(ctx, bson.M{"_id": id, "deleted_at": notDeletedFilter}, bson.M{"$set": updateFields})
+	if err != nil {
+		return err
+	}
 
-	return err
+	if res.MatchedCount == 0 {
+		return domain.ErrAnswerNotFound
+	}
+
+	return nil
 }
 
-func (r *answerRepository) Delete(ctx context.Context, id, userID bson.ObjectID) error {
-	_, err := r.db.Collection(domain.AnswerCollectionName).
-		DeleteOne(ctx, bson.M{"_id": id, "user_id": userID})
+func (r *answerRepository) Delete(ctx context.Context, id, deletedBy bson.ObjectID) error {
+	res, err := r.db.Collection(domain.AnswerCollectionName).
+		UpdateOne(ctx,
+			bson.M{"_id": id, "deleted_at": notDeletedFilter},
+			bson.M{"$set": bson.M{"deleted_at": time.Now(), "deleted_by": deletedBy}},
+		)
+	if err != nil {
+		return err
+	}
 
-	return err
+	if res.MatchedCount == 0 {
+		return domain.ErrAnswerNotFound
+	}
+
+	return nil
 }
 
 func (r *answerRepository) AddLike(ctx context.Context, id bson.ObjectID) error {
@@ -125,3 +243,33 @@ func (r *answerRepository) Verify(ctx context.Context, id bson.ObjectID) error {
 
 	return err
 }
+
+func (r *answerRepository) GetDeletedByID(ctx context.Context, id bson.ObjectID) (domain.Answer, error) {
+	var answer domain.Answer
+
+	err := r.db.Collection(domain.AnswerCollectionName).
+		FindOne(ctx, bson.M{"_id": id, "deleted_at": bson.M{"$exists": true}}).Decode(&answer)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.Answer{}, domain.ErrAnswerNotFound
+		}
+
+		return domain.Answer{}, err
+	}
+
+	return answer, nil
+}
+
+func (r *answerRepository) Restore(ctx context.Context, id bson.ObjectID) error {
+	_, err := r.db.Collection(domain.AnswerCollectionName).
+		UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$unset": bson.M{"deleted_at": "", "deleted_by": ""}})
+
+	return err
+}
+
+func (r *answerRepository) Purge(ctx context.Context, id bson.ObjectID) error {
+	_, err := r.db.Collection(domain.AnswerCollectionName).
+		DeleteOne(ctx, bson.M{"_id": id})
+
+	return err
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/Closi-App/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"time"
+)
+
+type CommentRepository interface {
+	Create(ctx context.Context, comment domain.Comment) error
+	GetAll(ctx context.Context, filter ...domain.CommentGetAllFilter) ([]domain.Comment, error)
+	GetByID(ctx context.Context, id bson.ObjectID) (domain.Comment, error)
+	Update(ctx context.Context, id bson.ObjectID, input domain.CommentUpdateInput) error
+	Delete(ctx context.Context, id bson.ObjectID) error
+
+	SetHidden(ctx context.Context, id bson.ObjectID, isHidden bool) error
+	DeleteAllByTarget(ctx context.Context, targetType domain.TargetType, targetID bson.ObjectID) error
+}
+
+type commentRepository struct {
+	*Repository
+}
+
+func NewCommentRepository(repository *Repository) CommentRepository {
+	targetIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}, {Key: "created_at", Value: 1}},
+	}
+
+	if _, err := repository.db.Collection(domain.CommentCollectionName).
+		Indexes().CreateOne(context.Background(), targetIndex); err != nil {
+		panic("error creating comment indexes: " + err.Error())
+	}
+
+	return &commentRepository{
+		Repository: repository,
+	}
+}
+
+func (r *commentRepository) Create(ctx context.Context, comment domain.Comment) error {
+	_, err := r.db.Collection(domain.CommentCollectionName).
+		InsertOne(ctx, comment)
+
+	return err
+}
+
+func (r *commentRepository) GetAll(ctx context.Context, filter ...domain.CommentGetAllFilter) ([]domain.Comment, error) {
+	filterFields := bson.M{"is_hidden": false}
+
+	if len(filter) > 0 {
+		f := filter[0]
+
+		if f.TargetType != nil {
+			filterFields["target_type"] = f.TargetType
+		}
+		if f.TargetID != nil {
+			filterFields["target_id"] = f.TargetID
+		}
+		if f.UserID != nil {
+			filterFields["user_id"] = f.UserID
+		}
+		if f.IncludeHidden {
+			delete(filterFields, "is_hidden")
+		}
+	}
+
+	cursor, err := r.db.Collection(domain.CommentCollectionName).
+		Find(ctx, filterFields, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []domain.Comment
+
+	if err = cursor.All(ctx, &comments); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+func (r *commentRepository) GetByID(ctx context.Context, id bson.ObjectID) (domain.Comment, error) {
+	var comment domain.Comment
+
+	err := r.db.Collection(domain.CommentCollectionName).
+		FindOne(ctx, bson.M{"_id": id}).Decode(&comment)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.Comment{}, domain.ErrCommentNotFound
+		}
+
+		return domain.Comment{}, err
+	}
+
+	return comment, nil
+}
+
+func (r *commentRepository) Update(ctx context.Context, id bson.ObjectID, input domain.CommentUpdateInput) error {
+	updateFields := bson.M{}
+
+	if input.Text != nil {
+		updateFields["text"] = input.Text
+	}
+
+	updateFields["updated_at"] = time.Now()
+
+	res, err := r.db.Collection(domain.CommentCollectionName).
+		UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": updateFields})
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return domain.ErrCommentNotFound
+	}
+
+	return nil
+}
+
+func (r *commentRepository) Delete(ctx context.Context, id bson.ObjectID) error {
+	res, err := r.db.Collection(domain.CommentCollectionName).
+		DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+
+	if res.DeletedCount == 0 {
+		return domain.ErrCommentNotFound
+	}
+
+	return nil
+}
+
+func (r *commentRepository) SetHidden(ctx context.Context, id bson.ObjectID, isHidden bool) error {
+	res, err := r.db.Collection(domain.CommentCollectionName).
+		UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"is_hidden": isHidden}})
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return domain.ErrCommentNotFound
+	}
+
+	return nil
+}
+
+func (r *commentRepository) DeleteAllByTarget(ctx context.Context, targetType domain.TargetType, targetID bson.ObjectID) error {
+	_, err := r.db.Collection(domain.CommentCollectionName).
+		DeleteMany(ctx, bson.M{"target_type": targetType, "target_id": targetID})
+
+	return err
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/Closi-App/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"time"
+)
+
+type QuestionDraftRepository interface {
+	Create(ctx context.Context, draft domain.QuestionDraft) error
+	GetAllByUserID(ctx context.Context, userID bson.ObjectID) ([]domain.QuestionDraft, error)
+	GetByID(ctx context.Context, id, userID bson.ObjectID) (domain.QuestionDraft, error)
+	Update(ctx context.Context, id, userID bson.ObjectID, input domain.QuestionDraftSaveInput) error
+	Delete(ctx context.Context, id, userID bson.ObjectID) error
+
+	Schedule(ctx context.Context, id, userID bson.ObjectID, publishAt time.Time) error
+	Claim(ctx context.Context, id, userID bson.ObjectID) (domain.QuestionDraft, error)
+	ClaimDue(ctx context.Context, now time.Time) (domain.QuestionDraft, error)
+}
+
+type questionDraftRepository struct {
+	*Repository
+}
+
+func NewQuestionDraftRepository(repository *Repository) QuestionDraftRepository {
+	userIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "updated_at", Value: -1}},
+	}
+	publishAtIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "publish_at", Value: 1}},
+		Options: options.Index().SetSparse(true),
+	}
+
+	if _, err := repository.db.Collection(domain.QuestionDraftCollectionName).
+		Indexes().CreateMany(context.Background(), []mongo.IndexModel{userIndex, publishAtIndex}); err != nil {
+		panic("error creating question draft indexes: " + err.Error())
+	}
+
+	return &questionDraftRepository{
+		Repository: repository,
+	}
+}
+
+func (r *questionDraftRepository) Create(ctx context.Context, draft domain.QuestionDraft) error {
+	_, err := r.db.Collection(domain.QuestionDraftCollectionName).
+		InsertOne(ctx, draft)
+
+	return err
+}
+
+func (r *questionDraftRepository) GetAllByUserID(ctx context.Context, userID bson.ObjectID) ([]domain.QuestionDraft, error) {
+	cursor, err := r.db.Collection(domain.QuestionDraftCollectionName).
+		Find(ctx, bson.M{"user_id": userID}, options.Find().SetSort(bson.D{{Key: "updated_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	var drafts []domain.QuestionDraft
+
+	if err = cursor.All(ctx, &drafts); err != nil {
+		return nil, err
+	}
+
+	return drafts, nil
+}
+
+func (r *questionDraftRepository) GetByID(ctx context.Context, id, userID bson.ObjectID) (domain.QuestionDraft, error) {
+	var draft domain.QuestionDraft
+
+	err := r.db.Collection(domain.QuestionDraftCollectionName).
+		FindOne(ctx, bson.M{"_id": id, "user_id": userID}).Decode(&draft)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.QuestionDraft{}, domain.ErrDraftNotFound
+		}
+
+		return domain.QuestionDraft{}, err
+	}
+
+	return draft, nil
+}
+
+func (r *questionDraftRepository) Update(ctx context.Context, id, userID bson.ObjectID, input domain.QuestionDraftSaveInput) error {
+	res, err := r.db.Collection(domain.QuestionDraftCollectionName).
+		UpdateOne(ctx, bson.M{"_id": id, "user_id": userID}, bson.M{"$set": bson.M{
+			"title":           input.Title,
+			"description":     input.Description,
+			"attachments_url": input.AttachmentsURL,
+			"tags":            input.Tags,
+			"points":          input.Points,
+			"updated_at":      time.Now(),
+		}})
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return domain.ErrDraftNotFound
+	}
+
+	return nil
+}
+
+func (r *questionDraftRepository) Delete(ctx context.Context, id, userID bson.ObjectID) error {
+	res, err := r.db.Collection(domain.QuestionDraftCollectionName).
+		DeleteOne(ctx, bson.M{"_id": id, "user_id": userID})
+	if err != nil {
+		return err
+	}
+
+	if res.DeletedCount == 0 {
+		return domain.ErrDraftNotFound
+	}
+
+	return nil
+}
+
+func (r *questionDraftRepository) Schedule(ctx context.Context, id, userID bson.ObjectID, publishAt time.Time) error {
+	res, err := r.db.Collection(domain.QuestionDraftCollectionName).
+		UpdateOne(ctx,
+			bson.M{"_id": id, "user_id": userID},
+			bson.M{
+				"$set":   bson.M{"publish_at": publishAt},
+				"$unset": bson.M{"publish_error": ""},
+			},
+		)
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return domain.ErrDraftNotFound
+	}
+
+	return nil
+}
+
+// Claim atomically takes the draft to be published by removing it, so it's published once even if requested twice.
+func (r *questionDraftRepository) Claim(ctx context.Context, id, userID bson.ObjectID) (domain.QuestionDraft, error) {
+	var draft domain.QuestionDraft
+
+	err := r.db.Collection(domain.QuestionDraftCollectionName).
+		FindOneAndDelete(ctx, bson.M{"_id": id, "user_id": userID}).Decode(&draft)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.QuestionDraft{}, domain.ErrDraftNotFound
+		}
+
+		return domain.QuestionDraft{}, err
+	}
+
+	return draft, nil
+}
+
+// ClaimDue atomically takes a draft which is due to be published by removing it, so it's published by one instance only.
+func (r *questionDraftRepository) ClaimDue(ctx context.Context, now time.Time) (domain.QuestionDraft, error) {
+	var draft domain.QuestionDraft
+
+	err := r.db.Collection(domain.QuestionDraftCollectionName).
+		FindOneAndDelete(ctx,
+			bson.M{"publish_at": bson.M{"$lte": now}},
+			options.FindOneAndDelete().SetSort(bson.D{{Key: "publish_at", Value: 1}}),
+		).Decode(&draft)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.QuestionDraft{}, domain.ErrDraftNotFound
+		}
+
+		return domain.QuestionDraft{}, err
+	}
+
+	return draft, nil
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/Closi-App/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type FavoriteRepository interface {
+	Add(ctx context.Context, favorite domain.Favorite) (added bool, err error)
+	GetAll(ctx context.Context, filter domain.FavoriteGetAllFilter) ([]domain.FavoriteQuestion, error)
+	Remove(ctx context.Context, userID, questionID bson.ObjectID) (removed bool, err error)
+	DeleteAllByQuestionID(ctx context.Context, questionID bson.ObjectID) error
+	DeleteAllByUserID(ctx context.Context, userID bson.ObjectID) ([]bson.ObjectID, error)
+}
+
+type favoriteRepository struct {
+	*Repository
+}
+
+func NewFavoriteRepository(repository *Repository) FavoriteRepository {
+	userQuestionIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "question_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	userCreatedAtIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+	}
+	questionIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "question_id", Value: 1}},
+	}
+
+	if _, err := repository.db.Collection(domain.FavoriteCollectionName).
+		Indexes().CreateMany(context.Background(), []mongo.IndexModel{userQuestionIndex, userCreatedAtIndex, questionIndex}); err != nil {
+		panic("error creating favorite indexes: " + err.Error())
+	}
+
+	return &favoriteRepository{
+		Repository: repository,
+	}
+}
+
+func (r *favoriteRepository) Add(ctx context.Context, favorite domain.Favorite) (bool, error) {
+	res, err := r.db.Collection(domain.FavoriteCollectionName).
+		UpdateOne(ctx,
+			bson.M{"user_id": favorite.UserID, "question_id": favorite.QuestionID},
+			bson.M{"$setOnInsert": bson.M{
+				"_id":        favorite.ID,
+				"created_at": favorite.CreatedAt,
+			}},
+			options.Update().SetUpsert(true),
+		)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return res.UpsertedCount > 0, nil
+}
+
+// GetAll returns the user favorite questions, the most recently added first.
+// Favorites of soft deleted questions are skipped.
+func (r *favoriteRepository) GetAll(ctx context.Context, filter domain.FavoriteGetAllFilter) ([]domain.FavoriteQuestion, error) {
+	cursor, err := r.db.Collection(domain.FavoriteCollectionName).
+		Aggregate(ctx, mongo.Pipeline{
+			{{Key: "$match", Value: bson.M{"user_id": filter.UserID}}},
+			{{Key: "$sort", Value: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}}},
+			{{Key: "$lookup", Value: bson.M{
+				"from":         domain.QuestionCollectionName,
+				"localField":   "question_id",
+				"foreignField": "_id",
+				"as":           "question",
+			}}},
+			{{Key: "$unwind", Value: "$question"}},
+			{{Key: "$match", Value: bson.M{"question.deleted_at": notDeletedFilter}}},
+			{{Key: "$skip", Value: filter.Skip()}},
+			{{Key: "$limit", Value: filter.Limit}},
+			{{Key: "$replaceWith", Value: bson.M{"$mergeObjects": bson.A{"$question", bson.M{"favorited_at": "$created_at"}}}}},
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	var favorites []domain.FavoriteQuestion
+
+	if err = cursor.All(ctx, &favorites); err != nil {
+		return nil, err
+	}
+
+	return favorites, nil
+}
+
+func (r *favoriteRepository) Remove(ctx context.Context, userID, questionID bson.ObjectID) (bool, error) {
+	res, err := r.db.Collection(domain.FavoriteCollectionName).
+		DeleteOne(ctx, bson.M{"user_id": userID, "question_id": questionID})
+	if err != nil {
+		return false, err
+	}
+
+	return res.DeletedCount > 0, nil
+}
+
+func (r *favoriteRepository) DeleteAllByQuestionID(ctx context.Context, questionID bson.ObjectID) error {
+	_, err := r.db.Collection(domain.FavoriteCollectionName).
+		DeleteMany(ctx, bson.M{"question_id": questionID})
+
+	return err
+}
+
+// DeleteAllByUserID removes the user favorites one by one and returns the questions they were removed from,
+// so a favorite removed concurrently by Remove is not counted twice.
+func (r *favoriteRepository) DeleteAllByUserID(ctx context.Context, userID bson.ObjectID) ([]bson.ObjectID, error) {
+	var questionIDs []bson.ObjectID
+
+	for {
+		var favorite domain.Favorite
+
+		err := r.db.Collection(domain.FavoriteCollectionName).
+			FindOneAndDelete(ctx, bson.M{"user_id": userID}).
+			Decode(&favorite)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return questionIDs, nil
+			}
+			return questionIDs, err
+		}
+
+		questionIDs = append(questionIDs, favorite.QuestionID)
+	}
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"github.com/Closi-App/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type FollowRepository interface {
+	Create(ctx context.Context, follow domain.Follow) error
+	GetAllByUserID(ctx context.Context, userID bson.ObjectID) ([]domain.Follow, error)
+	Delete(ctx context.Context, userID bson.ObjectID, targetType domain.FollowTarget, targetID bson.ObjectID) error
+	ReplaceTarget(ctx context.Context, targetType domain.FollowTarget, targetID, newTargetID bson.ObjectID) error
+	DeleteAllByTarget(ctx context.Context, targetType domain.FollowTarget, targetID bson.ObjectID) error
+	DeleteAllByUserID(ctx context.Context, userID bson.ObjectID) error
+}
+
+type followRepository struct {
+	*Repository
+}
+
+func NewFollowRepository(repository *Repository) FollowRepository {
+	userTargetIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "user_id", Value: 1},
+			{Key: "target_type", Value: 1},
+			{Key: "target_id", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	}
+	targetIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}},
+	}
+
+	if _, err := repository.db.Collection(domain.FollowCollectionName).
+		Indexes().CreateMany(context.Background(), []mongo.IndexModel{userTargetIndex, targetIndex}); err != nil {
+		panic("error creating follow indexes: " + err.Error())
+	}
+
+	return &followRepository{
+		Repository: repository,
+	}
+}
+
+// Create adds the follow unless the user already follows the target.
+func (r *followRepository) Create(ctx context.Context, follow domain.Follow) error {
+	_, err := r.db.Collection(domain.FollowCollectionName).
+		UpdateOne(ctx,
+			bson.M{"user_id": follow.UserID, "target_type": follow.TargetType, "target_id": follow.TargetID},
+			bson.M{"$setOnInsert": bson.M{
+				"_id":        follow.ID,
+				"created_at": follow.CreatedAt,
+			}},
+			options.Update().SetUpsert(true),
+		)
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (r *followRepository) GetAllByUserID(ctx context.Context, userID bson.ObjectID) ([]domain.Follow, error) {
+	cursor, err := r.db.Collection(domain.FollowCollectionName).
+		Find(ctx, bson.M{"user_id": userID}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	var follows []domain.Follow
+
+	if err = cursor.All(ctx, &follows); err != nil {
+		return nil, err
+	}
+
+	return follows, nil
+}
+
+func (r *followRepository) Delete(ctx context.Context, userID bson.ObjectID, targetType domain.FollowTarget, targetID bson.ObjectID) error {
+	_, err := r.db.Collection(domain.FollowCollectionName).
+		DeleteOne(ctx, bson.M{"user_id": userID, "target_type": targetType, "target_id": targetID})
+
+	return err
+}
+
+// ReplaceTarget moves the follows of the target to the new target,
+// the follows of users who already follow the new target are deleted instead.
+func (r *followRepository) ReplaceTarget(ctx context.Context, targetType domain.FollowTarget, targetID, newTargetID bson.ObjectID) error {
+	var followers []bson.ObjectID
+
+	if err := r.db.Collection(domain.FollowCollectionName).
+		Distinct(ctx, "user_id", bson.M{"target_type": targetType, "target_id": newTargetID}).
+		Decode(&followers); err != nil {
+		return err
+	}
+
+	if len(followers) > 0 {
+		if _, err := r.db.Collection(domain.FollowCollectionName).
+			DeleteMany(ctx, bson.M{
+				"target_type": targetType,
+				"target_id":   targetID,
+				"user_id":     bson.M{"$in": followers},
+			}); err != nil {
+			return err
+		}
+	}
+
+	_, err := r.db.Collection(domain.FollowCollectionName).
+		UpdateMany(ctx,
+			bson.M{"target_type": targetType, "target_id": targetID},
+			bson.M{"$set": bson.M{"target_id": newTargetID}},
+		)
+
+	return err
+}
+
+func (r *followRepository) DeleteAllByTarget(ctx context.Context, targetType domain.FollowTarget, targetID bson.ObjectID) error {
+	_, err := r.db.Collection(domain.FollowCollectionName).
+		DeleteMany(ctx, bson.M{"target_type": targetType, "target_id": targetID})
+
+	return err
+}
+
+func (r *followRepository) DeleteAllByUserID(ctx context.Context, userID bson.ObjectID) error {
+	_, err := r.db.Collection(domain.FollowCollectionName).
+		DeleteMany(ctx, bson.M{"user_id": userID})
+
+	return err
+}
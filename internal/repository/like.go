@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"github.com/Closi-App/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type AnswerLikeRepository interface {
+	Create(ctx context.Context, like domain.AnswerLike) (created bool, err error)
+	Delete(ctx context.Context, answerID, userID bson.ObjectID) (deleted bool, err error)
+	DeleteAllByAnswerID(ctx context.Context, answerID bson.ObjectID) error
+}
+
+type answerLikeRepository struct {
+	*Repository
+}
+
+func NewAnswerLikeRepository(repository *Repository) AnswerLikeRepository {
+	answerUserIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "answer_id", Value: 1}, {Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+
+	if _, err := repository.db.Collection(domain.AnswerLikeCollectionName).
+		Indexes().CreateOne(context.Background(), answerUserIndex); err != nil {
+		panic("error creating answer like indexes: " + err.Error())
+	}
+
+	return &answerLikeRepository{
+		Repository: repository,
+	}
+}
+
+// Create adds the like unless the user already likes the answer.
+func (r *answerLikeRepository) Create(ctx context.Context, like domain.AnswerLike) (bool, error) {
+	res, err := r.db.Collection(domain.AnswerLikeCollectionName).
+		UpdateOne(ctx,
+			bson.M{"answer_id": like.AnswerID, "user_id": like.UserID},
+			bson.M{"$setOnInsert": bson.M{
+				"_id":        like.ID,
+				"created_at": like.CreatedAt,
+			}},
+			options.Update().SetUpsert(true),
+		)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return res.UpsertedCount > 0, nil
+}
+
+func (r *answerLikeRepository) Delete(ctx context.Context, answerID, userID bson.ObjectID) (bool, error) {
+	res, err := r.db.Collection(domain.AnswerLikeCollectionName).
+		DeleteOne(ctx, bson.M{"answer_id": answerID, "user_id": userID})
+	if err != nil {
+		return false, err
+	}
+
+	return res.DeletedCount > 0, nil
+}
+
+func (r *answerLikeRepository) DeleteAllByAnswerID(ctx context.Context, answerID bson.ObjectID) error {
+	_, err := r.db.Collection(domain.AnswerLikeCollectionName).
+		DeleteMany(ctx, bson.M{"answer_id": answerID})
+
+	return err
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"github.com/Closi-App/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"time"
+)
+
+type NotificationRepository interface {
+	Create(ctx context.Context, notification domain.Notification) error
+	GetAll(ctx context.Context, filter domain.NotificationGetAllFilter) ([]domain.Notification, error)
+	CountUnread(ctx context.Context, userID bson.ObjectID) (int64, error)
+	CountByType(ctx context.Context, userID bson.ObjectID, since time.Time) (map[domain.EventType]int64, error)
+	MarkRead(ctx context.Context, id, userID bson.ObjectID) error
+	MarkAllRead(ctx context.Context, userID bson.ObjectID) error
+}
+
+type notificationRepository struct {
+	*Repository
+}
+
+func NewNotificationRepository(repository *Repository) NotificationRepository {
+	userIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "is_read", Value: 1}, {Key: "created_at", Value: -1}},
+	}
+
+	if _, err := repository.db.Collection(domain.NotificationCollectionName).
+		Indexes().CreateOne(context.Background(), userIndex); err != nil {
+		panic("error creating notification indexes: " + err.Error())
+	}
+
+	return &notificationRepository{
+		Repository: repository,
+	}
+}
+
+func (r *notificationRepository) Create(ctx context.Context, notification domain.Notification) error {
+	_, err := r.db.Collection(domain.NotificationCollectionName).
+		InsertOne(ctx, notification)
+
+	return err
+}
+
+func (r *notificationRepository) GetAll(ctx context.Context, filter domain.NotificationGetAllFilter) ([]domain.Notification, error) {
+	filterFields := bson.M{"user_id": filter.UserID}
+	if filter.UnreadOnly {
+		filterFields["is_read"] = false
+	}
+
+	cursor, err := r.db.Collection(domain.NotificationCollectionName).
+		Find(ctx, filterFields, options.Find().
+			SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+			SetSkip(int64(filter.Skip())).
+			SetLimit(int64(filter.Limit)))
+	if err != nil {
+		return nil, err
+	}
+
+	var notifications []domain.Notification
+
+	if err = cursor.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+func (r *notificationRepository) CountUnread(ctx context.Context, userID bson.ObjectID) (int64, error) {
+	return r.db.Collection(domain.NotificationCollectionName).
+		CountDocuments(ctx, bson.M{"user_id": userID, "is_read": false})
+}
+
+// CountByType counts notifications of the user created since the given time by their type.
+func (r *notificationRepository) CountByType(ctx context.Context, userID bson.ObjectID, since time.Time) (map[domain.EventType]int64, error) {
+	cursor, err := r.db.Collection(domain.NotificationCollectionName).
+		Aggregate(ctx, mongo.Pipeline{
+			{{Key: "$match", Value: bson.M{"user_id": userID, "created_at": bson.M{"$gte": since}}}},
+			{{Key: "$group", Value: bson.M{"_id": "$type", "count": bson.M{"$sum": 1}}}},
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []struct {
+		Type  domain.EventType `bson:"_id"`
+		Count int64            `bson:"count"`
+	}
+
+	if err = cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[domain.EventType]int64, len(groups))
+	for _, group := range groups {
+		counts[group.Type] = group.Count
+	}
+
+	return counts, nil
+}
+
+func (r *notificationRepository) MarkRead(ctx context.Context, id, userID bson.ObjectID) error {
+	res, err := r.db.Collection(domain.NotificationCollectionName).
+		UpdateOne(ctx, bson.M{"_id": id, "user_id": userID}, bson.M{"$set": bson.M{"is_read": true}})
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return domain.ErrNotificationNotFound
+	}
+
+	return nil
+}
+
+func (r *notificationRepository) MarkAllRead(ctx context.Context, userID bson.ObjectID) error {
+	_, err := r.db.Collection(domain.NotificationCollectionName).
+		UpdateMany(ctx, bson.M{"user_id": userID, "is_read": false}, bson.M{"$set": bson.M{"is_read": true}})
+
+	return err
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/Closi-App/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"time"
+)
+
+type EmailOutboxRepository interface {
+	Create(ctx context.Context, email domain.OutboxEmail) error
+	ClaimDue(ctx context.Context, now time.Time) (domain.OutboxEmail, error)
+	MarkSent(ctx context.Context, id bson.ObjectID) error
+	MarkFailed(ctx context.Context, id bson.ObjectID, attempts int, nextAttemptAt time.Time, lastError string) error
+	GetAll(ctx context.Context, filter domain.EmailGetAllFilter) ([]domain.OutboxEmail, error)
+	Retry(ctx context.Context, id bson.ObjectID) error
+	FailPending(ctx context.Context, to string, lastError string) (int64, error)
+}
+
+type emailOutboxRepository struct {
+	*Repository
+}
+
+func NewEmailOutboxRepository(repository *Repository) EmailOutboxRepository {
+	dueIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+	}
+
+	sentIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "sent_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(int32(domain.EmailSentRetention.Seconds())),
+	}
+
+	recipientIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "to", Value: 1}, {Key: "status", Value: 1}},
+		Options: options.Index().SetCollation(caseInsensitiveCollation),
+	}
+
+	if _, err := repository.db.Collection(domain.EmailOutboxCollectionName).
+		Indexes().CreateMany(context.Background(), []mongo.IndexModel{dueIndex, sentIndex, recipientIndex}); err != nil {
+		panic("error creating email outbox indexes: " + err.Error())
+	}
+
+	return &emailOutboxRepository{
+		Repository: repository,
+	}
+}
+
+func (r *emailOutboxRepository) Create(ctx context.Context, email domain.OutboxEmail) error {
+	_, err := r.db.Collection(domain.EmailOutboxCollectionName).
+		InsertOne(ctx, email)
+
+	return err
+}
+
+// ClaimDue returns a single pending email due for sending and postpones it by domain.EmailDeliveryLease,
+// so other workers don't send it at the same time.
+func (r *emailOutboxRepository) ClaimDue(ctx context.Context, now time.Time) (domain.OutboxEmail, error) {
+	var email domain.OutboxEmail
+
+	err := r.db.Collection(domain.EmailOutboxCollectionName).
+		FindOneAndUpdate(ctx,
+			bson.M{"status": domain.PendingEmailStatus, "next_attempt_at": bson.M{"$lte": now}},
+			bson.M{"$set": bson.M{"next_attempt_at": now.Add(domain.EmailDeliveryLease)}},
+			options.FindOneAndUpdate().
+				SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+				SetReturnDocument(options.After),
+		).Decode(&email)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.OutboxEmail{}, domain.ErrEmailNotFound
+		}
+
+		return domain.OutboxEmail{}, err
+	}
+
+	return email, nil
+}
+
+func (r *emailOutboxRepository) MarkSent(ctx context.Context, id bson.ObjectID) error {
+	_, err := r.db.Collection(domain.EmailOutboxCollectionName).
+		UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+			"$set":   bson.M{"status": domain.SentEmailStatus, "sent_at": time.Now()},
+			"$inc":   bson.M{"attempts": 1},
+			"$unset": bson.M{"last_error": ""},
+		})
+
+	return err
+}
+
+// MarkFailed records the failed attempt, the email becomes a dead letter after domain.EmailMaxAttempts.
+func (r *emailOutboxRepository) MarkFailed(ctx context.Context, id bson.ObjectID, attempts int, nextAttemptAt time.Time, lastError string) error {
+	status := domain.PendingEmailStatus
+	if attempts >= domain.EmailMaxAttempts {
+		status = domain.FailedEmailStatus
+	}
+
+	_, err := r.db.Collection(domain.EmailOutboxCollectionName).
+		UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{
+			"status":          status,
+			"attempts":        attempts,
+			"next_attempt_at": nextAttemptAt,
+			"last_error":      lastError,
+		}})
+
+	return err
+}
+
+func (r *emailOutboxRepository) GetAll(ctx context.Context, filter domain.EmailGetAllFilter) ([]domain.OutboxEmail, error) {
+	filterFields := bson.M{}
+	if filter.Status != "" {
+		filterFields["status"] = filter.Status
+	}
+
+	cursor, err := r.db.Collection(domain.EmailOutboxCollectionName).
+		Find(ctx, filterFields, options.Find().
+			SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+			SetSkip(int64(filter.Skip())).
+			SetLimit(int64(filter.Limit)))
+	if err != nil {
+		return nil, err
+	}
+
+	var emails []domain.OutboxEmail
+
+	if err = cursor.All(ctx, &emails); err != nil {
+		return nil, err
+	}
+
+	return emails, nil
+}
+
+// Retry puts a dead letter back to the outbox with a fresh set of attempts.
+func (r *emailOutboxRepository) Retry(ctx context.Context, id bson.ObjectID) error {
+	res, err := r.db.Collection(domain.EmailOutboxCollectionName).
+		UpdateOne(ctx,
+			bson.M{"_id": id, "status": domain.FailedEmailStatus},
+			bson.M{"$set": bson.M{
+				"status":          domain.PendingEmailStatus,
+				"attempts":        0,
+				"next_attempt_at": time.Now(),
+			}},
+		)
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return domain.ErrEmailNotFound
+	}
+
+	return nil
+}
+
+// FailPending turns pending emails to the address into dead letters and returns their number,
+// the address is matched ignoring the case.
+func (r *emailOutboxRepository) FailPending(ctx context.Context, to string, lastError string) (int64, error) {
+	res, err := r.db.Collection(domain.EmailOutboxCollectionName).
+		UpdateMany(ctx,
+			bson.M{"to": to, "status": domain.PendingEmailStatus},
+			bson.M{"$set": bson.M{
+				"status":     domain.FailedEmailStatus,
+				"last_error": lastError,
+			}},
+			options.Update().SetCollation(caseInsensitiveCollation),
+		)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.ModifiedCount, nil
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/Closi-App/backend/internal/domain"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"sort"
 	"time"
 )
 
@@ -13,15 +15,49 @@ type QuestionRepository interface {
 	Create(ctx context.Context, question domain.Question) error
 	GetAll(ctx context.Context, filter ...domain.QuestionGetAllFilter) ([]domain.Question, error)
 	GetByID(ctx context.Context, id bson.ObjectID) (domain.Question, error)
-	Update(ctx context.Context, id, userID bson.ObjectID, input domain.QuestionUpdateInput) error
-	Delete(ctx context.Context, id, userID bson.ObjectID) error
+	GetSimilar(ctx context.Context, filter domain.QuestionSimilarFilter) ([]domain.Question, error)
+	GetFeed(ctx context.Context, filter domain.FeedFilter) ([]domain.Question, error)
+	Update(ctx context.Context, id bson.ObjectID, input domain.QuestionUpdateInput) error
+	Delete(ctx context.Context, id, deletedBy bson.ObjectID) error
+
+	AdjustVotes(ctx context.Context, id bson.ObjectID, upVotes, downVotes int) error
+	AddViews(ctx context.Context, views domain.QuestionViews) error
+	AdjustFavorites(ctx context.Context, id bson.ObjectID, delta int) error
+	ReplaceTag(ctx context.Context, tagID, newTagID bson.ObjectID) error
+	SetDuplicateOf(ctx context.Context, id bson.ObjectID, originalID *bson.ObjectID) error
+	SetAcceptedAnswer(ctx context.Context, id, answerID bson.ObjectID) error
+
+	RaiseBounty(ctx context.Context, id bson.ObjectID, points uint, expiresAt time.Time) error
+	ReleaseBounty(ctx context.Context, id bson.ObjectID) (bool, error)
+	ClaimExpiredBounty(ctx context.Context, now time.Time) (domain.Question, error)
+	ClaimExpiringBounty(ctx context.Context, now time.Time) (domain.Question, error)
+	GetUnanswered(ctx context.Context, filter domain.DigestQuestionsFilter) ([]domain.Question, error)
+
+	GetDeletedByID(ctx context.Context, id bson.ObjectID) (domain.Question, error)
+	Restore(ctx context.Context, id bson.ObjectID) error
+	Purge(ctx context.Context, id bson.ObjectID) error
 }
 
+// notDeletedFilter matches documents which aren't soft deleted.
+var notDeletedFilter = bson.M{"$exists": false}
+
 type questionRepository struct {
 	*Repository
 }
 
 func NewQuestionRepository(repository *Repository) QuestionRepository {
+	textIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "title", Value: "text"}, {Key: "description", Value: "text"}},
+		Options: options.Index().
+			SetWeights(bson.D{{Key: "title", Value: 3}, {Key: "description", Value: 1}}).
+			SetDefaultLanguage("none"),
+	}
+
+	if _, err := repository.db.Collection(domain.QuestionCollectionName).
+		Indexes().CreateOne(context.Background(), textIndex); err != nil {
+		panic("error creating question indexes: " + err.Error())
+	}
+
 	return &questionRepository{
 		Repository: repository,
 	}
@@ -35,16 +71,23 @@ func (r *questionRepository) Create(ctx context.Context, question domain.Questio
 }
 
 func (r *questionRepository) GetAll(ctx context.Context, filter ...domain.QuestionGetAllFilter) ([]domain.Question, error) {
-	filterFields := bson.M{}
+	filterFields := bson.M{"deleted_at": notDeletedFilter}
+	sort := domain.NewestQuestionSort
+	limit := 0
 
 	if len(filter) > 0 {
 		f := filter[0]
 
+		if f.Sort != "" {
+			sort = f.Sort
+		}
+		limit = f.Limit
+
 		if f.Title != nil {
 			filterFields["title"] = f.Title
 		}
 		if f.Tag != nil {
-			filterFields["tags"] = bson.M{"$in": f.Tag}
+			filterFields["tags"] = f.Tag
 		}
 		if f.CountryID != nil {
 			filterFields["country_id"] = f.CountryID
@@ -52,10 +95,45 @@ func (r *questionRepository) GetAll(ctx context.Context, filter ...domain.Questi
 		if f.UserID != nil {
 			filterFields["user_id"] = f.UserID
 		}
+		if f.DeletedBefore != nil {
+			filterFields["deleted_at"] = bson.M{"$lte": f.DeletedBefore}
+		}
 	}
 
-	cursor, err := r.db.Collection(domain.QuestionCollectionName).
-		Find(ctx, filterFields)
+	var (
+		cursor *mongo.Cursor
+		err    error
+	)
+
+	findOptions := options.Find()
+	if limit > 0 {
+		findOptions.SetLimit(int64(limit))
+	}
+
+	switch sort {
+	case domain.HotQuestionSort:
+		pipeline := mongo.Pipeline{
+			{{Key: "$match", Value: filterFields}},
+			{{Key: "$addFields", Value: bson.M{"hotness": hotnessExpression(time.Now())}}},
+			{{Key: "$sort", Value: bson.D{{Key: "hotness", Value: -1}, {Key: "created_at", Value: -1}}}},
+			{{Key: "$project", Value: bson.M{"hotness": 0}}},
+		}
+		if limit > 0 {
+			pipeline = append(pipeline, bson.D{{Key: "$limit", Value: limit}})
+		}
+
+		cursor, err = r.db.Collection(domain.QuestionCollectionName).
+			Aggregate(ctx, pipeline)
+	case domain.ViewsQuestionSort:
+		cursor, err = r.db.Collection(domain.QuestionCollectionName).
+			Find(ctx, filterFields, findOptions.SetSort(bson.D{{Key: "views", Value: -1}, {Key: "created_at", Value: -1}}))
+	case domain.TopQuestionSort:
+		cursor, err = r.db.Collection(domain.QuestionCollectionName).
+			Find(ctx, filterFields, findOptions.SetSort(bson.D{{Key: "score", Value: -1}, {Key: "created_at", Value: -1}}))
+	default:
+		cursor, err = r.db.Collection(domain.QuestionCollectionName).
+			Find(ctx, filterFields, findOptions.SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -73,7 +151,7 @@ func (r *questionRepository) GetByID(ctx context.Context, id bson.ObjectID) (dom
 	var question domain.Question
 
 	err := r.db.Collection(domain.QuestionCollectionName).
-		FindOne(ctx, bson.M{"_id": id}).Decode(&question)
+		FindOne(ctx, bson.M{"_id": id, "deleted_at": notDeletedFilter}).Decode(&question)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return domain.Question{}, domain.ErrQuestionNotFound
@@ -85,7 +163,197 @@ func (r *questionRepository) GetByID(ctx context.Context, id bson.ObjectID) (dom
 	return question, nil
 }
 
-func (r *questionRepository) Update(ctx context.Context, id, userID bson.ObjectID, input domain.QuestionUpdateInput) error {
+// similarQuestion is a similar question candidate along with its relevance.
+type similarQuestion struct {
+	domain.Question `bson:",inline"`
+	Relevance       float64 `bson:"relevance"`
+}
+
+// GetSimilar ranks the questions matching the text and the questions sharing the tags together,
+// so the text only adds to the relevance and questions sharing tags are found even without common words.
+func (r *questionRepository) GetSimilar(ctx context.Context, filter domain.QuestionSimilarFilter) ([]domain.Question, error) {
+	tags := filter.Tags
+	if tags == nil {
+		tags = []bson.ObjectID{}
+	}
+
+	var candidates []similarQuestion
+
+	if filter.Text != "" {
+		match := r.similarMatch(filter)
+		match["$text"] = bson.M{"$search": filter.Text}
+
+		textCandidates, err := r.similarCandidates(ctx, match, bson.M{"$meta": "textScore"}, tags, filter.Limit)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, textCandidates...)
+	}
+
+	if len(tags) > 0 {
+		match := r.similarMatch(filter)
+		match["tags"] = bson.M{"$in": tags}
+
+		tagCandidates, err := r.similarCandidates(ctx, match, bson.M{"$literal": 0}, tags, filter.Limit)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, tagCandidates...)
+	}
+
+	// questions matching both the text and the tags keep the relevance of the text match, which includes the tags
+	relevant := make(map[bson.ObjectID]similarQuestion, len(candidates))
+	for _, candidate := range candidates {
+		if existing, ok := relevant[candidate.ID]; !ok || candidate.Relevance > existing.Relevance {
+			relevant[candidate.ID] = candidate
+		}
+	}
+
+	similar := make([]similarQuestion, 0, len(relevant))
+	for _, candidate := range relevant {
+		similar = append(similar, candidate)
+	}
+
+	sort.Slice(similar, func(i, j int) bool {
+		if similar[i].Relevance != similar[j].Relevance {
+			return similar[i].Relevance > similar[j].Relevance
+		}
+		return similar[i].Score > similar[j].Score
+	})
+
+	if len(similar) > filter.Limit {
+		similar = similar[:filter.Limit]
+	}
+
+	questions := make([]domain.Question, len(similar))
+	for i, candidate := range similar {
+		questions[i] = candidate.Question
+	}
+
+	return questions, nil
+}
+
+func (r *questionRepository) similarMatch(filter domain.QuestionSimilarFilter) bson.M {
+	match := bson.M{
+		"country_id":   filter.CountryID,
+		"deleted_at":   notDeletedFilter,
+		"duplicate_of": bson.M{"$exists": false},
+	}
+	if filter.ExcludeID != nil {
+		match["_id"] = bson.M{"$ne": filter.ExcludeID}
+	}
+
+	return match
+}
+
+// similarCandidates returns the most relevant questions of the match, the relevance is the text score
+// plus the weight of every shared tag.
+func (r *questionRepository) similarCandidates(ctx context.Context, match bson.M, textScore interface{}, tags []bson.ObjectID, limit int) ([]similarQuestion, error) {
+	cursor, err := r.db.Collection(domain.QuestionCollectionName).
+		Aggregate(ctx, mongo.Pipeline{
+			{{Key: "$match", Value: match}},
+			{{Key: "$addFields", Value: bson.M{"relevance": bson.M{"$add": bson.A{
+				textScore,
+				bson.M{"$multiply": bson.A{
+					bson.M{"$size": bson.M{"$setIntersection": bson.A{bson.M{"$ifNull": bson.A{"$tags", bson.A{}}}, tags}}},
+					domain.QuestionSharedTagWeight,
+				}},
+			}}}}},
+			{{Key: "$sort", Value: bson.D{{Key: "relevance", Value: -1}, {Key: "score", Value: -1}}}},
+			{{Key: "$limit", Value: limit}},
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []similarQuestion
+
+	if err = cursor.All(ctx, &candidates); err != nil {
+		return nil, err
+	}
+
+	return candidates, nil
+}
+
+func (r *questionRepository) GetFeed(ctx context.Context, filter domain.FeedFilter) ([]domain.Question, error) {
+	var followed bson.A
+	if len(filter.TagIDs) > 0 {
+		followed = append(followed, bson.M{"tags": bson.M{"$in": filter.TagIDs}})
+	}
+	if len(filter.UserIDs) > 0 {
+		followed = append(followed, bson.M{"user_id": bson.M{"$in": filter.UserIDs}})
+	}
+	if len(followed) == 0 {
+		return nil, nil
+	}
+
+	match := bson.M{
+		"deleted_at": notDeletedFilter,
+		"user_id":    bson.M{"$ne": filter.ExcludeUserID},
+		"$and":       bson.A{bson.M{"$or": followed}, feedBeforeFilter(filter.Before)},
+	}
+
+	cursor, err := r.db.Collection(domain.QuestionCollectionName).
+		Find(ctx, match, options.Find().
+			SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+			SetLimit(int64(filter.Limit)))
+	if err != nil {
+		return nil, err
+	}
+
+	var questions []domain.Question
+
+	if err = cursor.All(ctx, &questions); err != nil {
+		return nil, err
+	}
+
+	return questions, nil
+}
+
+// GetUnanswered returns new questions without answers from the country or with the tags of the filter.
+func (r *questionRepository) GetUnanswered(ctx context.Context, filter domain.DigestQuestionsFilter) ([]domain.Question, error) {
+	scope := bson.A{bson.M{"country_id": filter.CountryID}}
+	if len(filter.TagIDs) > 0 {
+		scope = append(scope, bson.M{"tags": bson.M{"$in": filter.TagIDs}})
+	}
+
+	cursor, err := r.db.Collection(domain.QuestionCollectionName).
+		Aggregate(ctx, mongo.Pipeline{
+			{{Key: "$match", Value: bson.M{
+				"deleted_at": notDeletedFilter,
+				"user_id":    bson.M{"$ne": filter.ExcludeUserID},
+				"created_at": bson.M{"$gte": filter.Since},
+				"$or":        scope,
+			}}},
+			{{Key: "$sort", Value: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}}},
+			{{Key: "$lookup", Value: bson.M{
+				"from":         domain.AnswerCollectionName,
+				"localField":   "_id",
+				"foreignField": "question_id",
+				"pipeline": mongo.Pipeline{
+					{{Key: "$match", Value: bson.M{"deleted_at": notDeletedFilter}}},
+					{{Key: "$limit", Value: 1}},
+				},
+				"as": "answers",
+			}}},
+			{{Key: "$match", Value: bson.M{"answers": bson.M{"$size": 0}}}},
+			{{Key: "$limit", Value: filter.Limit}},
+			{{Key: "$unset", Value: "answers"}},
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	var questions []domain.Question
+
+	if err = cursor.All(ctx, &questions); err != nil {
+		return nil, err
+	}
+
+	return questions, nil
+}
+
+func (r *questionRepository) Update(ctx context.Context, id bson.ObjectID, input domain.QuestionUpdateInput) error {
 	updateFields := bson.M{}
 
 	if input.Title != nil {
@@ -103,18 +371,285 @@ func (r *questionRepository) Update(ctx context.Context, id, userID bson.ObjectI
 	if input.Points != nil {
 		updateFields["points"] = input.Points
 	}
+	if input.BountyExpiresAt != nil {
+		updateFields["bounty_expires_at"] = input.BountyExpiresAt
+	}
 
 	updateFields["updated_at"] = time.Now()
 
+	res, err := r.db.Collection(domain.QuestionCollectionName).
+		UpdateOne(ctx, bson.M{"_id": id, "deleted_at": notDeletedFilter}, bson.M{"$set": updateFields})
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return domain.ErrQuestionNotFound
+	}
+
+	return nil
+}
+
+func (r *questionRepository) Delete(ctx context.Context, id, deletedBy bson.ObjectID) error {
+	res, err := r.db.Collection(domain.QuestionCollectionName).
+		UpdateOne(ctx,
+			bson.M{"_id": id, "deleted_at": notDeletedFilter},
+			bson.M{"$set": bson.M{"deleted_at": time.Now(), "deleted_by": deletedBy}},
+		)
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return domain.ErrQuestionNotFound
+	}
+
+	return nil
+}
+
+func (r *questionRepository) AdjustVotes(ctx context.Context, id bson.ObjectID, upVotes, downVotes int) error {
+	_, err := r.db.Collection(domain.QuestionCollectionName).
+		UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$inc": bson.M{
+			"up_votes":   upVotes,
+			"down_votes": downVotes,
+			"score":      upVotes - downVotes,
+		}})
+
+	return err
+}
+
+func (r *questionRepository) AddViews(ctx context.Context, views domain.QuestionViews) error {
 	_, err := r.db.Collection(domain.QuestionCollectionName).
-		UpdateOne(ctx, bson.M{"_id": id, "user_id": userID}, bson.M{"$set": updateFields})
+		UpdateOne(ctx, bson.M{"_id": views.QuestionID}, bson.M{
+			"$inc": bson.M{"views": views.Views},
+			"$max": bson.M{"unique_viewers": views.UniqueViewers},
+		})
 
 	return err
 }
 
-func (r *questionRepository) Delete(ctx context.Context, id, userID bson.ObjectID) error {
+func (r *questionRepository) AdjustFavorites(ctx context.Context, id bson.ObjectID, delta int) error {
 	_, err := r.db.Collection(domain.QuestionCollectionName).
-		DeleteOne(ctx, bson.M{"_id": id, "user_id": userID})
+		UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$inc": bson.M{"favorites_count": delta}})
 
 	return err
 }
+
+func (r *questionRepository) ReplaceTag(ctx context.Context, tagID, newTagID bson.ObjectID) error {
+	filter := bson.M{"tags": tagID}
+
+	if _, err := r.db.Collection(domain.QuestionCollectionName).
+		UpdateMany(ctx, filter, bson.M{"$addToSet": bson.M{"tags": newTagID}}); err != nil {
+		return err
+	}
+
+	_, err := r.db.Collection(domain.QuestionCollectionName).
+		UpdateMany(ctx, filter, bson.M{"$pull": bson.M{"tags": tagID}})
+
+	return err
+}
+
+func (r *questionRepository) SetDuplicateOf(ctx context.Context, id bson.ObjectID, originalID *bson.ObjectID) error {
+	update := bson.M{"$set": bson.M{"duplicate_of": originalID}}
+	if originalID == nil {
+		update = bson.M{"$unset": bson.M{"duplicate_of": ""}}
+	}
+
+	res, err := r.db.Collection(domain.QuestionCollectionName).
+		UpdateOne(ctx, bson.M{"_id": id, "deleted_at": notDeletedFilter}, update)
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return domain.ErrQuestionNotFound
+	}
+
+	return nil
+}
+
+func (r *questionRepository) SetAcceptedAnswer(ctx context.Context, id, answerID bson.ObjectID) error {
+	res, err := r.db.Collection(domain.QuestionCollectionName).
+		UpdateOne(ctx,
+			bson.M{"_id": id, "accepted_answer_id": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"accepted_answer_id": answerID}},
+		)
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return domain.ErrAnswerAlreadyVerified
+	}
+
+	return nil
+}
+
+func (r *questionRepository) RaiseBounty(ctx context.Context, id bson.ObjectID, points uint, expiresAt time.Time) error {
+	res, err := r.db.Collection(domain.QuestionCollectionName).
+		UpdateOne(ctx,
+			bson.M{
+				"_id":                id,
+				"deleted_at":         notDeletedFilter,
+				"accepted_answer_id": bson.M{"$exists": false},
+				"bounty_released_at": bson.M{"$exists": false},
+			},
+			bson.M{
+				"$inc":   bson.M{"points": points},
+				"$set":   bson.M{"bounty_expires_at": expiresAt, "updated_at": time.Now()},
+				"$unset": bson.M{"bounty_reminded_at": ""},
+			},
+		)
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return domain.ErrQuestionBountyClosed
+	}
+
+	return nil
+}
+
+// ReleaseBounty marks the question bounty as released and reports whether the caller is the one who released it,
+// so the escrowed points are paid out exactly once.
+func (r *questionRepository) ReleaseBounty(ctx context.Context, id bson.ObjectID) (bool, error) {
+	res, err := r.db.Collection(domain.QuestionCollectionName).
+		UpdateOne(ctx,
+			bson.M{
+				"_id":                id,
+				"points":             bson.M{"$gt": 0},
+				"bounty_released_at": bson.M{"$exists": false},
+			},
+			bson.M{"$set": bson.M{"bounty_released_at": time.Now()}},
+		)
+	if err != nil {
+		return false, err
+	}
+
+	return res.MatchedCount > 0, nil
+}
+
+// ClaimExpiredBounty releases a single question whose bounty expired without an accepted answer.
+func (r *questionRepository) ClaimExpiredBounty(ctx context.Context, now time.Time) (domain.Question, error) {
+	var question domain.Question
+
+	err := r.db.Collection(domain.QuestionCollectionName).
+		FindOneAndUpdate(ctx,
+			bson.M{
+				"deleted_at":         notDeletedFilter,
+				"points":             bson.M{"$gt": 0},
+				"accepted_answer_id": bson.M{"$exists": false},
+				"bounty_released_at": bson.M{"$exists": false},
+				"bounty_expires_at":  bson.M{"$lte": now},
+			},
+			bson.M{"$set": bson.M{"bounty_released_at": now}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&question)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.Question{}, domain.ErrQuestionNotFound
+		}
+
+		return domain.Question{}, err
+	}
+
+	return question, nil
+}
+
+// ClaimExpiringBounty marks a single question whose bounty expires soon as reminded.
+func (r *questionRepository) ClaimExpiringBounty(ctx context.Context, now time.Time) (domain.Question, error) {
+	var question domain.Question
+
+	err := r.db.Collection(domain.QuestionCollectionName).
+		FindOneAndUpdate(ctx,
+			bson.M{
+				"deleted_at":         notDeletedFilter,
+				"points":             bson.M{"$gt": 0},
+				"accepted_answer_id": bson.M{"$exists": false},
+				"bounty_released_at": bson.M{"$exists": false},
+				"bounty_reminded_at": bson.M{"$exists": false},
+				"bounty_expires_at":  bson.M{"$gt": now, "$lte": now.Add(domain.BountyReminderWindow)},
+			},
+			bson.M{"$set": bson.M{"bounty_reminded_at": now}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&question)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.Question{}, domain.ErrQuestionNotFound
+		}
+
+		return domain.Question{}, err
+	}
+
+	return question, nil
+}
+
+func (r *questionRepository) GetDeletedByID(ctx context.Context, id bson.ObjectID) (domain.Question, error) {
+	var question domain.Question
+
+	err := r.db.Collection(domain.QuestionCollectionName).
+		FindOne(ctx, bson.M{"_id": id, "deleted_at": bson.M{"$exists": true}}).Decode(&question)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.Question{}, domain.ErrQuestionNotFound
+		}
+
+		return domain.Question{}, err
+	}
+
+	return question, nil
+}
+
+func (r *questionRepository) Restore(ctx context.Context, id bson.ObjectID) error {
+	_, err := r.db.Collection(domain.QuestionCollectionName).
+		UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$unset": bson.M{"deleted_at": "", "deleted_by": ""}})
+
+	return err
+}
+
+// Purge removes the soft deleted question for good, domain.ErrQuestionNotFound is returned
+// if it has already been purged, so the caller knows whether this call removed it.
+func (r *questionRepository) Purge(ctx context.Context, id bson.ObjectID) error {
+	res, err := r.db.Collection(domain.QuestionCollectionName).
+		DeleteOne(ctx, bson.M{"_id": id, "deleted_at": bson.M{"$exists": true}})
+	if err != nil {
+		return err
+	}
+
+	if res.DeletedCount == 0 {
+		return domain.ErrQuestionNotFound
+	}
+
+	return nil
+}
+
+// hotnessExpression builds an aggregation expression for the question rank
+// that decays with time: score / (ageInHours + 2) ^ gravity.
+func hotnessExpression(now time.Time) bson.M {
+	ageInHours := bson.M{"$divide": bson.A{
+		bson.M{"$subtract": bson.A{now, "$created_at"}},
+		time.Hour.Milliseconds(),
+	}}
+
+	return bson.M{"$divide": bson.A{
+		bson.M{"$ifNull": bson.A{"$score", 0}},
+		bson.M{"$pow": bson.A{
+			bson.M{"$add": bson.A{ageInHours, 2}},
+			domain.QuestionHotGravity,
+		}},
+	}}
+}
+
+// feedBeforeFilter matches documents created before the feed cursor, documents created at the same time
+// are ordered by id.
+func feedBeforeFilter(before *domain.FeedCursor) bson.M {
+	if before == nil {
+		return bson.M{}
+	}
+
+	return bson.M{"$or": bson.A{
+		bson.M{"created_at": bson.M{"$lt": before.CreatedAt}},
+		bson.M{"created_at": before.CreatedAt, "_id": bson.M{"$lt": before.ID}},
+	}}
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Closi-App/backend/internal/domain"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	dbRealtimeChannel      = "realtime"
+	dbEventStreamKeyFormat = "event_stream:%s"
+	dbEventStreamField     = "event"
+)
+
+// RealtimeRepository fans out realtime messages to all server instances through Redis pub/sub
+// and keeps recent events of every user in a Redis stream.
+type RealtimeRepository interface {
+	Publish(ctx context.Context, message domain.RealtimeMessage) error
+	Listen(ctx context.Context, handler func(message domain.RealtimeMessage)) error
+	AppendStream(ctx context.Context, userID bson.ObjectID, event domain.Event) (string, error)
+	GetStream(ctx context.Context, userID bson.ObjectID, afterID string) ([]domain.RealtimeMessage, error)
+}
+
+type realtimeRepository struct {
+	*Repository
+}
+
+func NewRealtimeRepository(repository *Repository) RealtimeRepository {
+	return &realtimeRepository{
+		Repository: repository,
+	}
+}
+
+func (r *realtimeRepository) Publish(ctx context.Context, message domain.RealtimeMessage) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	return r.rdb.Publish(ctx, dbRealtimeChannel, payload).Err()
+}
+
+// Listen passes messages published by any instance to the handler until the context is done.
+func (r *realtimeRepository) Listen(ctx context.Context, handler func(message domain.RealtimeMessage)) error {
+	pubsub := r.rdb.Subscribe(ctx, dbRealtimeChannel)
+	defer pubsub.Close()
+
+	// wait for the subscription confirmation, so the connection errors are returned
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return err
+	}
+
+	messages := pubsub.Channel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return errors.New("realtime subscription is closed")
+			}
+
+			var message domain.RealtimeMessage
+			if err := json.Unmarshal([]byte(msg.Payload), &message); err != nil {
+				r.log.Error().Err(err).Msg("error decoding realtime message")
+				continue
+			}
+
+			handler(message)
+		}
+	}
+}
+
+// AppendStream adds the event to the user event stream and returns its ID.
+func (r *realtimeRepository) AppendStream(ctx context.Context, userID bson.ObjectID, event domain.Event) (string, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+
+	key := fmt.Sprintf(dbEventStreamKeyFormat, userID.Hex())
+
+	id, err := r.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: key,
+		MaxLen: domain.EventStreamMaxLength,
+		Approx: true,
+		Values: map[string]any{dbEventStreamField: payload},
+	}).Result()
+	if err != nil {
+		return "", err
+	}
+
+	if err = r.rdb.Expire(ctx, key, domain.EventStreamTTL).Err(); err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// GetStream returns the kept events of the user which came after the event with the given ID.
+func (r *realtimeRepository) GetStream(ctx context.Context, userID bson.ObjectID, afterID string) ([]domain.RealtimeMessage, error) {
+	entries, err := r.rdb.XRange(ctx, fmt.Sprintf(dbEventStreamKeyFormat, userID.Hex()), "("+afterID, "+").Result()
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]domain.RealtimeMessage, 0, len(entries))
+
+	for _, entry := range entries {
+		payload, ok := entry.Values[dbEventStreamField].(string)
+		if !ok {
+			continue
+		}
+
+		var event domain.Event
+		if err = json.Unmarshal([]byte(payload), &event); err != nil {
+			r.log.Error().Err(err).Msgf("error decoding stream event (%s)", entry.ID)
+			continue
+		}
+
+		messages = append(messages, domain.RealtimeMessage{
+			ID:      entry.ID,
+			Channel: domain.StreamChannelOf(userID),
+			Event:   event,
+		})
+	}
+
+	return messages, nil
+}
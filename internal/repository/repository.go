@@ -5,8 +5,12 @@ import (
 	imgbb "github.com/JohnNON/ImgBB"
 	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
+// caseInsensitiveCollation compares strings ignoring the case, queries using it need an index with the same collation.
+var caseInsensitiveCollation = &options.Collation{Locale: "en", Strength: 2}
+
 type Repository struct {
 	log   *logger.Logger
 	db    *mongo.Database
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/Closi-App/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type RevisionRepository interface {
+	Create(ctx context.Context, revision domain.Revision) error
+	GetAll(ctx context.Context, targetType domain.TargetType, targetID bson.ObjectID) ([]domain.Revision, error)
+	GetByID(ctx context.Context, id bson.ObjectID) (domain.Revision, error)
+	NextNumber(ctx context.Context, targetType domain.TargetType, targetID bson.ObjectID) (int, error)
+	ReplaceTag(ctx context.Context, tagID, newTagID bson.ObjectID) error
+	DeleteAllByTarget(ctx context.Context, targetType domain.TargetType, targetID bson.ObjectID) error
+}
+
+type revisionRepository struct {
+	*Repository
+}
+
+func NewRevisionRepository(repository *Repository) RevisionRepository {
+	targetNumberIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}, {Key: "number", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+
+	if _, err := repository.db.Collection(domain.RevisionCollectionName).
+		Indexes().CreateOne(context.Background(), targetNumberIndex); err != nil {
+		panic("error creating revision indexes: " + err.Error())
+	}
+
+	return &revisionRepository{
+		Repository: repository,
+	}
+}
+
+func (r *revisionRepository) Create(ctx context.Context, revision domain.Revision) error {
+	_, err := r.db.Collection(domain.RevisionCollectionName).
+		InsertOne(ctx, revision)
+
+	return err
+}
+
+func (r *revisionRepository) GetAll(ctx context.Context, targetType domain.TargetType, targetID bson.ObjectID) ([]domain.Revision, error) {
+	cursor, err := r.db.Collection(domain.RevisionCollectionName).
+		Find(ctx,
+			bson.M{"target_type": targetType, "target_id": targetID},
+			options.Find().SetSort(bson.D{{Key: "number", Value: 1}}),
+		)
+	if err != nil {
+		return nil, err
+	}
+
+	var revisions []domain.Revision
+
+	if err = cursor.All(ctx, &revisions); err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+func (r *revisionRepository) GetByID(ctx context.Context, id bson.ObjectID) (domain.Revision, error) {
+	var revision domain.Revision
+
+	err := r.db.Collection(domain.RevisionCollectionName).
+		FindOne(ctx, bson.M{"_id": id}).Decode(&revision)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.Revision{}, domain.ErrRevisionNotFound
+		}
+
+		return domain.Revision{}, err
+	}
+
+	return revision, nil
+}
+
+// NextNumber allocates the number of the next target revision with an atomic counter, so concurrent edits don't collide.
+// The counter of the target which has revisions from before the counters starts from their count.
+func (r *revisionRepository) NextNumber(ctx context.Context, targetType domain.TargetType, targetID bson.ObjectID) (int, error) {
+	counterID := revisionCounterID(targetType, targetID)
+	counters := r.db.Collection(domain.RevisionCounterCollectionName)
+
+	if err := counters.FindOne(ctx, bson.M{"_id": counterID}).Err(); err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return 0, err
+		}
+
+		count, err := r.db.Collection(domain.RevisionCollectionName).
+			CountDocuments(ctx, bson.M{"target_type": targetType, "target_id": targetID})
+		if err != nil {
+			return 0, err
+		}
+
+		if _, err = counters.UpdateOne(ctx,
+			bson.M{"_id": counterID},
+			bson.M{"$setOnInsert": bson.M{"number": count}},
+			options.Update().SetUpsert(true),
+		); err != nil && !mongo.IsDuplicateKeyError(err) {
+			return 0, err
+		}
+	}
+
+	var counter struct {
+		Number int `bson:"number"`
+	}
+
+	err := counters.FindOneAndUpdate(ctx,
+		bson.M{"_id": counterID},
+		bson.M{"$inc": bson.M{"number": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&counter)
+	if err != nil {
+		return 0, err
+	}
+
+	return counter.Number, nil
+}
+
+// ReplaceTag replaces the tag in the question content snapshots, so rollbacks don't bring back the merged tag.
+func (r *revisionRepository) ReplaceTag(ctx context.Context, tagID, newTagID bson.ObjectID) error {
+	filter := bson.M{"target_type": domain.QuestionTarget, "content.tags": tagID}
+
+	if _, err := r.db.Collection(domain.RevisionCollectionName).
+		UpdateMany(ctx, filter, bson.M{"$addToSet": bson.M{"content.tags": newTagID}}); err != nil {
+		return err
+	}
+
+	_, err := r.db.Collection(domain.RevisionCollectionName).
+		UpdateMany(ctx, filter, bson.M{"$pull": bson.M{"content.tags": tagID}})
+
+	return err
+}
+
+func (r *revisionRepository) DeleteAllByTarget(ctx context.Context, targetType domain.TargetType, targetID bson.ObjectID) error {
+	if _, err := r.db.Collection(domain.RevisionCollectionName).
+		DeleteMany(ctx, bson.M{"target_type": targetType, "target_id": targetID}); err != nil {
+		return err
+	}
+
+	_, err := r.db.Collection(domain.RevisionCounterCollectionName).
+		DeleteOne(ctx, bson.M{"_id": revisionCounterID(targetType, targetID)})
+
+	return err
+}
+
+func revisionCounterID(targetType domain.TargetType, targetID bson.ObjectID) string {
+	return string(targetType) + ":" + targetID.Hex()
+}
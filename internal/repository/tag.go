@@ -6,14 +6,26 @@ import (
 	"github.com/Closi-App/backend/internal/domain"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"regexp"
 )
 
 type TagRepository interface {
 	Create(ctx context.Context, tag domain.Tag) error
+	GetOrCreate(ctx context.Context, tag domain.Tag) (domain.Tag, error)
 	GetByID(ctx context.Context, id bson.ObjectID) (domain.Tag, error)
+	GetByName(ctx context.Context, countryID bson.ObjectID, name string) (domain.Tag, error)
 	GetAll(ctx context.Context) ([]domain.Tag, error)
 	GetAllByCountryID(ctx context.Context, countryID bson.ObjectID) ([]domain.Tag, error)
+	Update(ctx context.Context, id bson.ObjectID, input domain.TagUpdateInput) error
 	Delete(ctx context.Context, id bson.ObjectID) error
+
+	AddSynonyms(ctx context.Context, id bson.ObjectID, names ...string) error
+	RemoveSynonym(ctx context.Context, id bson.ObjectID, name string) error
+	RecountUsage(ctx context.Context, id bson.ObjectID) error
+	Search(ctx context.Context, countryID bson.ObjectID, prefix string, limit int) ([]domain.Tag, error)
+	GetTrending(ctx context.Context, filter domain.TagTrendingFilter) ([]domain.TrendingTag, error)
+	AdjustUsage(ctx context.Context, ids []bson.ObjectID, delta int) error
 }
 
 type tagRepository struct {
@@ -21,6 +33,23 @@ type tagRepository struct {
 }
 
 func NewTagRepository(repository *Repository) TagRepository {
+	countryNameIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "country_id", Value: 1}, {Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+
+	countrySynonymsIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "country_id", Value: 1}, {Key: "synonyms", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"synonyms": bson.M{"$exists": true}}),
+	}
+
+	if _, err := repository.db.Collection(domain.TagCollectionName).
+		Indexes().CreateMany(context.Background(), []mongo.IndexModel{countryNameIndex, countrySynonymsIndex}); err != nil {
+		panic("error creating tag indexes: " + err.Error())
+	}
+
 	return &tagRepository{
 		Repository: repository,
 	}
@@ -33,6 +62,41 @@ func (r *tagRepository) Create(ctx context.Context, tag domain.Tag) error {
 	return err
 }
 
+func (r *tagRepository) GetOrCreate(ctx context.Context, tag domain.Tag) (domain.Tag, error) {
+	var result domain.Tag
+
+	// synonyms redirect to their canonical tag
+	err := r.db.Collection(domain.TagCollectionName).
+		FindOne(ctx, bson.M{"country_id": tag.CountryID, "synonyms": tag.Name}).Decode(&result)
+	if err == nil {
+		return result, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.Tag{}, err
+	}
+
+	filter := bson.M{"country_id": tag.CountryID, "name": tag.Name}
+
+	err = r.db.Collection(domain.TagCollectionName).
+		FindOneAndUpdate(ctx,
+			filter,
+			bson.M{"$setOnInsert": bson.M{"_id": tag.ID, "usage_count": 0}},
+			options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+		).Decode(&result)
+	if err != nil {
+		// concurrent upsert of the same tag has won the race
+		if mongo.IsDuplicateKeyError(err) {
+			err = r.db.Collection(domain.TagCollectionName).
+				FindOne(ctx, filter).Decode(&result)
+		}
+		if err != nil {
+			return domain.Tag{}, err
+		}
+	}
+
+	return result, nil
+}
+
 func (r *tagRepository) GetByID(ctx context.Context, id bson.ObjectID) (domain.Tag, error) {
 	var tag domain.Tag
 
@@ -49,6 +113,25 @@ func (r *tagRepository) GetByID(ctx context.Context, id bson.ObjectID) (domain.T
 	return tag, nil
 }
 
+func (r *tagRepository) GetByName(ctx context.Context, countryID bson.ObjectID, name string) (domain.Tag, error) {
+	var tag domain.Tag
+
+	err := r.db.Collection(domain.TagCollectionName).
+		FindOne(ctx, bson.M{
+			"country_id": countryID,
+			"$or":        bson.A{bson.M{"name": name}, bson.M{"synonyms": name}},
+		}).Decode(&tag)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.Tag{}, domain.ErrTagNotFound
+		}
+
+		return domain.Tag{}, err
+	}
+
+	return tag, nil
+}
+
 func (r *tagRepository) GetAll(ctx context.Context) ([]domain.Tag, error) {
 	cursor, err := r.db.Collection(domain.TagCollectionName).
 		Find(ctx, bson.M{})
@@ -81,9 +164,172 @@ func (r *tagRepository) GetAllByCountryID(ctx context.Context, countryID bson.Ob
 	return tags, nil
 }
 
+func (r *tagRepository) Update(ctx context.Context, id bson.ObjectID, input domain.TagUpdateInput) error {
+	setFields := bson.M{}
+	unsetFields := bson.M{}
+
+	if input.Name != nil {
+		setFields["name"] = input.Name
+	}
+	for lang, description := range input.Descriptions {
+		if description == "" {
+			unsetFields["descriptions."+lang] = ""
+		} else {
+			setFields["descriptions."+lang] = description
+		}
+	}
+
+	update := bson.M{}
+	if len(setFields) > 0 {
+		update["$set"] = setFields
+	}
+	if len(unsetFields) > 0 {
+		update["$unset"] = unsetFields
+	}
+	if len(update) == 0 {
+		return nil
+	}
+
+	res, err := r.db.Collection(domain.TagCollectionName).
+		UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrTagAlreadyExists
+		}
+
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return domain.ErrTagNotFound
+	}
+
+	return nil
+}
+
 func (r *tagRepository) Delete(ctx context.Context, id bson.ObjectID) error {
 	_, err := r.db.Collection(domain.TagCollectionName).
 		DeleteOne(ctx, bson.M{"_id": id})
 
 	return err
 }
+
+func (r *tagRepository) Search(ctx context.Context, countryID bson.ObjectID, prefix string, limit int) ([]domain.Tag, error) {
+	cursor, err := r.db.Collection(domain.TagCollectionName).
+		Find(ctx,
+			bson.M{
+				"country_id": countryID,
+				"name":       bson.M{"$regex": "^" + regexp.QuoteMeta(prefix)},
+			},
+			options.Find().
+				SetSort(bson.D{{Key: "usage_count", Value: -1}, {Key: "name", Value: 1}}).
+				SetLimit(int64(limit)),
+		)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []domain.Tag
+
+	if err = cursor.All(ctx, &tags); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (r *tagRepository) GetTrending(ctx context.Context, filter domain.TagTrendingFilter) ([]domain.TrendingTag, error) {
+	cursor, err := r.db.Collection(domain.QuestionCollectionName).
+		Aggregate(ctx, mongo.Pipeline{
+			{{Key: "$match", Value: bson.M{
+				"country_id": filter.CountryID,
+				"created_at": bson.M{"$gte": filter.Since},
+				"deleted_at": notDeletedFilter,
+			}}},
+			{{Key: "$unwind", Value: "$tags"}},
+			{{Key: "$group", Value: bson.M{"_id": "$tags", "questions": bson.M{"$sum": 1}}}},
+			{{Key: "$sort", Value: bson.D{{Key: "questions", Value: -1}, {Key: "_id", Value: 1}}}},
+			{{Key: "$limit", Value: filter.Limit}},
+			{{Key: "$lookup", Value: bson.M{
+				"from":         domain.TagCollectionName,
+				"localField":   "_id",
+				"foreignField": "_id",
+				"as":           "tag",
+			}}},
+			{{Key: "$unwind", Value: "$tag"}},
+			{{Key: "$replaceRoot", Value: bson.M{
+				"newRoot": bson.M{"$mergeObjects": bson.A{"$tag", bson.M{"questions": "$questions"}}},
+			}}},
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []domain.TrendingTag
+
+	if err = cursor.All(ctx, &tags); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func (r *tagRepository) AdjustUsage(ctx context.Context, ids []bson.ObjectID, delta int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := r.db.Collection(domain.TagCollectionName).
+		UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, bson.M{"$inc": bson.M{"usage_count": delta}})
+
+	return err
+}
+
+func (r *tagRepository) AddSynonyms(ctx context.Context, id bson.ObjectID, names ...string) error {
+	res, err := r.db.Collection(domain.TagCollectionName).
+		UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$addToSet": bson.M{"synonyms": bson.M{"$each": names}}})
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrTagAlreadyExists
+		}
+
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return domain.ErrTagNotFound
+	}
+
+	return nil
+}
+
+func (r *tagRepository) RemoveSynonym(ctx context.Context, id bson.ObjectID, name string) error {
+	res, err := r.db.Collection(domain.TagCollectionName).
+		UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$pull": bson.M{"synonyms": name}})
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return domain.ErrTagNotFound
+	}
+
+	// empty arrays are indexed too, so they would collide in the unique synonyms index
+	_, err = r.db.Collection(domain.TagCollectionName).
+		UpdateOne(ctx, bson.M{"_id": id, "synonyms": bson.M{"$size": 0}}, bson.M{"$unset": bson.M{"synonyms": ""}})
+
+	return err
+}
+
+func (r *tagRepository) RecountUsage(ctx context.Context, id bson.ObjectID) error {
+	count, err := r.db.Collection(domain.QuestionCollectionName).
+		CountDocuments(ctx, bson.M{"tags": id, "deleted_at": notDeletedFilter})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Collection(domain.TagCollectionName).
+		UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"usage_count": count}})
+
+	return err
+}
@@ -20,17 +20,19 @@ type UserRepository interface {
 	GetByReferralCode(ctx context.Context, referralCode string) (domain.User, error)
 	Update(ctx context.Context, id bson.ObjectID, input domain.UserUpdateInput) error
 	UpdateSettings(ctx context.Context, id bson.ObjectID, input domain.UserSettingsUpdateInput) error
+	GetDigestDue(ctx context.Context, now time.Time, limit int) ([]domain.User, error)
+	ClaimDigest(ctx context.Context, id bson.ObjectID, previousNextAt *time.Time, digest domain.UserDigest) (bool, error)
 	Delete(ctx context.Context, id bson.ObjectID) error
 
-	AdjustPoints(ctx context.Context, id bson.ObjectID, pointsAmount int) error
-	AddFavorite(ctx context.Context, id, questionID bson.ObjectID) error
-	RemoveFavorite(ctx context.Context, id, questionID bson.ObjectID) error
+	AdjustPoints(ctx context.Context, id bson.ObjectID, pointsAmount int) (uint, error)
+	AdjustPointsClamped(ctx context.Context, id bson.ObjectID, pointsAmount int) (applied int, balance uint, err error)
 	AddAchievement(ctx context.Context, id, achievementID bson.ObjectID) error
 	RemoveAchievement(ctx context.Context, id, achievementID bson.ObjectID) error
 	SetSubscription(ctx context.Context, id bson.ObjectID, subscription domain.Subscription) error
 	Confirm(ctx context.Context, id bson.ObjectID) error
 	Block(ctx context.Context, id bson.ObjectID) error
 	Unblock(ctx context.Context, id bson.ObjectID) error
+	SetEmailBounce(ctx context.Context, email string, bounce domain.EmailBounce) (domain.User, error)
 
 	CreateSession(ctx context.Context, refreshToken string, userID bson.ObjectID, expiration time.Duration) error
 	GetSession(ctx context.Context, refreshToken string) (userID bson.ObjectID, err error)
@@ -45,13 +47,18 @@ func NewUserRepository(repository *Repository) UserRepository {
 		Keys:    bson.M{"email": 1},
 		Options: options.Index().SetUnique(true),
 	}
+	// bounce notifications may spell the email in a different case
+	emailCaseInsensitiveIndex := mongo.IndexModel{
+		Keys:    bson.M{"email": 1},
+		Options: options.Index().SetName("email_case_insensitive").SetCollation(caseInsensitiveCollation),
+	}
 	usernameIndex := mongo.IndexModel{
 		Keys:    bson.M{"username": 1},
 		Options: options.Index().SetUnique(true),
 	}
 
 	if _, err := repository.db.Collection(domain.UserCollectionName).
-		Indexes().CreateMany(context.Background(), []mongo.IndexModel{emailIndex, usernameIndex}); err != nil {
+		Indexes().CreateMany(context.Background(), []mongo.IndexModel{emailIndex, emailCaseInsensitiveIndex, usernameIndex}); err != nil {
 		panic("error creating user indexes: " + err.Error())
 	}
 
@@ -122,6 +129,7 @@ func (r *userRepository) GetByReferralCode(ctx context.Context, referralCode str
 }
 
 func (r *userRepository) Update(ctx context.Context, id bson.ObjectID, input domain.UserUpdateInput) error {
+	update := bson.M{}
 	updateFields := bson.M{}
 
 	if input.Name != nil {
@@ -133,6 +141,8 @@ func (r *userRepository) Update(ctx context.Context, id bson.ObjectID, input dom
 	if input.Email != nil {
 		updateFields["email"] = input.Email
 		updateFields["is_confirmed"] = false
+		// the new email isn't known to bounce
+		update["$unset"] = bson.M{"email_bounce": ""}
 	}
 	if input.Password != nil {
 		updateFields["password"] = input.Password
@@ -142,9 +152,10 @@ func (r *userRepository) Update(ctx context.Context, id bson.ObjectID, input dom
 	}
 
 	updateFields["updated_at"] = time.Now()
+	update["$set"] = updateFields
 
 	_, err := r.db.Collection(domain.UserCollectionName).
-		UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": updateFields})
+		UpdateOne(ctx, bson.M{"_id": id}, update)
 
 	return err
 }
@@ -161,18 +172,82 @@ func (r *userRepository) UpdateSettings(ctx context.Context, id bson.ObjectID, i
 	if input.Appearance != nil {
 		updateFields["settings.appearance"] = input.Appearance
 	}
-	if input.EmailNotifications != nil {
-		updateFields["settings.email_notifications"] = input.EmailNotifications
+	if input.Timezone != nil {
+		updateFields["settings.timezone"] = input.Timezone
+	}
+	if input.EmailPreferences != nil {
+		if input.EmailPreferences.Answers != nil {
+			updateFields["settings.email_preferences.answers"] = input.EmailPreferences.Answers
+		}
+		if input.EmailPreferences.AcceptedAnswers != nil {
+			updateFields["settings.email_preferences.accepted_answers"] = input.EmailPreferences.AcceptedAnswers
+		}
+		if input.EmailPreferences.Bounties != nil {
+			updateFields["settings.email_preferences.bounties"] = input.EmailPreferences.Bounties
+		}
+		if input.EmailPreferences.Digest != nil {
+			updateFields["settings.email_preferences.digest"] = input.EmailPreferences.Digest
+		}
 	}
 
 	updateFields["updated_at"] = time.Now()
 
+	update := bson.M{"$set": updateFields}
+
+	// the digest is rescheduled by the digest job
+	if input.Timezone != nil || (input.EmailPreferences != nil && input.EmailPreferences.Digest != nil) {
+		update["$unset"] = bson.M{"digest.next_at": ""}
+	}
+
 	_, err := r.db.Collection(domain.UserCollectionName).
-		UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": updateFields})
+		UpdateOne(ctx, bson.M{"_id": id}, update)
 
 	return err
 }
 
+// GetDigestDue returns users whose digest must be sent or who have to be scheduled for one.
+func (r *userRepository) GetDigestDue(ctx context.Context, now time.Time, limit int) ([]domain.User, error) {
+	cursor, err := r.db.Collection(domain.UserCollectionName).
+		Find(ctx,
+			bson.M{
+				"settings.email_preferences.digest": bson.M{"$in": bson.A{domain.DailyDigest, domain.WeeklyDigest}},
+				"$or": bson.A{
+					bson.M{"digest.next_at": bson.M{"$exists": false}},
+					bson.M{"digest.next_at": bson.M{"$lte": now}},
+				},
+			},
+			options.Find().SetLimit(int64(limit)),
+		)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []domain.User
+
+	if err = cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// ClaimDigest sets the digest schedule of the user if it wasn't changed since it was read,
+// so every digest is sent once when several instances run the digest job.
+func (r *userRepository) ClaimDigest(ctx context.Context, id bson.ObjectID, previousNextAt *time.Time, digest domain.UserDigest) (bool, error) {
+	filter := bson.M{"_id": id, "digest.next_at": bson.M{"$exists": false}}
+	if previousNextAt != nil {
+		filter["digest.next_at"] = previousNextAt
+	}
+
+	res, err := r.db.Collection(domain.UserCollectionName).
+		UpdateOne(ctx, filter, bson.M{"$set": bson.M{"digest": digest}})
+	if err != nil {
+		return false, err
+	}
+
+	return res.MatchedCount > 0, nil
+}
+
 func (r *userRepository) Delete(ctx context.Context, id bson.ObjectID) error {
 	_, err := r.db.Collection(domain.UserCollectionName).
 		DeleteOne(ctx, bson.M{"_id": id})
@@ -180,25 +255,76 @@ func (r *userRepository) Delete(ctx context.Context, id bson.ObjectID) error {
 	return err
 }
 
-func (r *userRepository) AdjustPoints(ctx context.Context, id bson.ObjectID, pointsAmount int) error {
-	_, err := r.db.Collection(domain.UserCollectionName).
-		UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$inc": bson.M{"points": pointsAmount}})
+// AdjustPoints returns the new points balance of the user, the balance can't become negative,
+// so domain.ErrUserInsufficientPoints is returned and nothing is changed if the user has less points than taken.
+func (r *userRepository) AdjustPoints(ctx context.Context, id bson.ObjectID, pointsAmount int) (uint, error) {
+	var user domain.User
 
-	return err
-}
+	filter := bson.M{"_id": id}
+	if pointsAmount < 0 {
+		filter["points"] = bson.M{"$gte": -pointsAmount}
+	}
 
-func (r *userRepository) AddFavorite(ctx context.Context, id, questionID bson.ObjectID) error {
-	_, err := r.db.Collection(domain.UserCollectionName).
-		UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$addToSet": bson.M{"favorites": questionID}})
+	err := r.db.Collection(domain.UserCollectionName).
+		FindOneAndUpdate(ctx,
+			filter,
+			bson.M{"$inc": bson.M{"points": pointsAmount}},
+			options.FindOneAndUpdate().
+				SetReturnDocument(options.After).
+				SetProjection(bson.M{"points": 1}),
+		).Decode(&user)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			if pointsAmount < 0 {
+				count, err := r.db.Collection(domain.UserCollectionName).
+					CountDocuments(ctx, bson.M{"_id": id}, options.Count().SetLimit(1))
+				if err != nil {
+					return 0, err
+				}
+
+				if count > 0 {
+					return 0, domain.ErrUserInsufficientPoints
+				}
+			}
+
+			return 0, domain.ErrUserNotFound
+		}
 
-	return err
+		return 0, err
+	}
+
+	return user.Points, nil
 }
 
-func (r *userRepository) RemoveFavorite(ctx context.Context, id, questionID bson.ObjectID) error {
-	_, err := r.db.Collection(domain.UserCollectionName).
-		UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$pull": bson.M{"favorites": questionID}})
+// AdjustPointsClamped adds the points to the balance, a negative amount takes as much as the user has,
+// so the returned applied amount can be less than asked.
+func (r *userRepository) AdjustPointsClamped(ctx context.Context, id bson.ObjectID, pointsAmount int) (int, uint, error) {
+	var user domain.User
 
-	return err
+	err := r.db.Collection(domain.UserCollectionName).
+		FindOneAndUpdate(ctx,
+			bson.M{"_id": id},
+			mongo.Pipeline{
+				{{Key: "$set", Value: bson.M{"points": bson.M{"$max": bson.A{
+					0,
+					bson.M{"$add": bson.A{"$points", pointsAmount}},
+				}}}}},
+			},
+			options.FindOneAndUpdate().
+				SetReturnDocument(options.Before).
+				SetProjection(bson.M{"points": 1}),
+		).Decode(&user)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return 0, 0, domain.ErrUserNotFound
+		}
+
+		return 0, 0, err
+	}
+
+	balance := max(0, int(user.Points)+pointsAmount)
+
+	return balance - int(user.Points), uint(balance), nil
 }
 
 func (r *userRepository) AddAchievement(ctx context.Context, id, achievementID bson.ObjectID) error {
@@ -243,6 +369,27 @@ func (r *userRepository) Unblock(ctx context.Context, id bson.ObjectID) error {
 	return err
 }
 
+// SetEmailBounce marks the email of the user as undeliverable and returns the user, the email is matched ignoring
+// the case. domain.ErrUserNotFound is returned if no user has the email or it's already marked.
+func (r *userRepository) SetEmailBounce(ctx context.Context, email string, bounce domain.EmailBounce) (domain.User, error) {
+	var user domain.User
+
+	err := r.db.Collection(domain.UserCollectionName).
+		FindOneAndUpdate(ctx,
+			bson.M{"email": email, "email_bounce": nil},
+			bson.M{"$set": bson.M{"email_bounce": bounce}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After).SetCollation(caseInsensitiveCollation),
+		).Decode(&user)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.User{}, domain.ErrUserNotFound
+		}
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
 func (r *userRepository) CreateSession(ctx context.Context, refreshToken string, userID bson.ObjectID, expiration time.Duration) error {
 	key := fmt.Sprintf(dbSessionKeyFormat, refreshToken)
 	value := userID.Hex()
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/Closi-App/backend/internal/domain"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"strconv"
+	"time"
+)
+
+const (
+	dbQuestionViewKeyFormat    = "question_view:%s:%s"
+	dbQuestionViewersKeyFormat = "question_viewers:%s"
+	dbQuestionViewsPendingKey  = "question_views:pending"
+	dbQuestionViewsFlushingKey = "question_views:flushing"
+	dbQuestionViewsFlushLock   = "question_views:flush_lock"
+)
+
+// mergePendingViewsScript adds the pending views to the views being flushed, views left from a failed flush are kept.
+var mergePendingViewsScript = redis.NewScript(`
+local entries = redis.call("HGETALL", KEYS[1])
+for i = 1, #entries, 2 do
+	redis.call("HINCRBY", KEYS[2], entries[i], entries[i + 1])
+end
+redis.call("DEL", KEYS[1])
+return #entries / 2
+`)
+
+// releaseLockScript removes the lock only if it's still held with the token, so an expired lock taken by another
+// instance isn't released.
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// removeFlushedViewsScript subtracts the flushed views of the question and removes it once no views are left.
+var removeFlushedViewsScript = redis.NewScript(`
+if redis.call("HINCRBY", KEYS[1], ARGV[1], -tonumber(ARGV[2])) <= 0 then
+	redis.call("HDEL", KEYS[1], ARGV[1])
+end
+return 1
+`)
+
+type QuestionViewRepository interface {
+	Record(ctx context.Context, questionID bson.ObjectID, viewer string, dedupTTL time.Duration) error
+	GetPending(ctx context.Context) ([]domain.QuestionViews, error)
+	RemoveFlushed(ctx context.Context, views domain.QuestionViews) error
+	LockFlush(ctx context.Context, ttl time.Duration) (token string, locked bool, err error)
+	UnlockFlush(ctx context.Context, token string) error
+}
+
+type questionViewRepository struct {
+	*Repository
+}
+
+func NewQuestionViewRepository(repository *Repository) QuestionViewRepository {
+	return &questionViewRepository{
+		Repository: repository,
+	}
+}
+
+// Record counts the view unless the viewer has already viewed the question within dedupTTL.
+// Unique viewers are estimated with a HyperLogLog per question.
+func (r *questionViewRepository) Record(ctx context.Context, questionID bson.ObjectID, viewer string, dedupTTL time.Duration) error {
+	isNew, err := r.rdb.SetNX(ctx, fmt.Sprintf(dbQuestionViewKeyFormat, questionID.Hex(), viewer), 1, dedupTTL).Result()
+	if err != nil {
+		return err
+	}
+
+	if !isNew {
+		return nil
+	}
+
+	viewersKey := fmt.Sprintf(dbQuestionViewersKeyFormat, questionID.Hex())
+
+	// viewers of questions nobody views anymore expire, unique_viewers keeps the last estimate
+	_, err = r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HIncrBy(ctx, dbQuestionViewsPendingKey, questionID.Hex(), 1)
+		pipe.PFAdd(ctx, viewersKey, viewer)
+		pipe.Expire(ctx, viewersKey, domain.QuestionViewersTTL)
+		return nil
+	})
+
+	return err
+}
+
+// GetPending returns views recorded since the last flush along with views left from a failed flush.
+// The views are kept until they're removed with RemoveFlushed, so they aren't lost if the flush fails.
+func (r *questionViewRepository) GetPending(ctx context.Context) ([]domain.QuestionViews, error) {
+	// the script is atomic, so views recorded meanwhile go to a fresh pending hash
+	if err := mergePendingViewsScript.Run(ctx, r.rdb, []string{dbQuestionViewsPendingKey, dbQuestionViewsFlushingKey}).Err(); err != nil {
+		return nil, err
+	}
+
+	pending, err := r.rdb.HGetAll(ctx, dbQuestionViewsFlushingKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]domain.QuestionViews, 0, len(pending))
+
+	for id, value := range pending {
+		questionID, err := bson.ObjectIDFromHex(id)
+		if err != nil {
+			continue
+		}
+
+		count, err := strconv.Atoi(value)
+		if err != nil {
+			continue
+		}
+
+		uniqueViewers, err := r.rdb.PFCount(ctx, fmt.Sprintf(dbQuestionViewersKeyFormat, id)).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		views = append(views, domain.QuestionViews{
+			QuestionID:    questionID,
+			Views:         count,
+			UniqueViewers: int(uniqueViewers),
+		})
+	}
+
+	return views, nil
+}
+
+// RemoveFlushed removes the views once they're saved.
+func (r *questionViewRepository) RemoveFlushed(ctx context.Context, views domain.QuestionViews) error {
+	return removeFlushedViewsScript.Run(ctx, r.rdb, []string{dbQuestionViewsFlushingKey}, views.QuestionID.Hex(), views.Views).Err()
+}
+
+// LockFlush takes the lock of the views flush, so the views being flushed aren't saved by several instances at once.
+// The lock expires after ttl in case the instance holding it dies.
+func (r *questionViewRepository) LockFlush(ctx context.Context, ttl time.Duration) (string, bool, error) {
+	token := bson.NewObjectID().Hex()
+
+	locked, err := r.rdb.SetNX(ctx, dbQuestionViewsFlushLock, token, ttl).Result()
+	if err != nil {
+		return "", false, err
+	}
+
+	return token, locked, nil
+}
+
+func (r *questionViewRepository) UnlockFlush(ctx context.Context, token string) error {
+	return releaseLockScript.Run(ctx, r.rdb, []string{dbQuestionViewsFlushLock}, token).Err()
+}
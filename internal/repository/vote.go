@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/Closi-App/backend/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"time"
+)
+
+type QuestionVoteRepository interface {
+	Set(ctx context.Context, vote domain.QuestionVote) (previous domain.QuestionVote, err error)
+	SetReputation(ctx context.Context, questionID, userID bson.ObjectID, reputation int) error
+	Delete(ctx context.Context, questionID, userID bson.ObjectID) (previous domain.QuestionVote, err error)
+	DeleteAllByQuestionID(ctx context.Context, questionID bson.ObjectID) error
+}
+
+type questionVoteRepository struct {
+	*Repository
+}
+
+func NewQuestionVoteRepository(repository *Repository) QuestionVoteRepository {
+	questionUserIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "question_id", Value: 1}, {Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+
+	if _, err := repository.db.Collection(domain.QuestionVoteCollectionName).
+		Indexes().CreateOne(context.Background(), questionUserIndex); err != nil {
+		panic("error creating question vote indexes: " + err.Error())
+	}
+
+	return &questionVoteRepository{
+		Repository: repository,
+	}
+}
+
+// Set returns the previous vote of the user, its value is zero if the user hasn't voted yet.
+func (r *questionVoteRepository) Set(ctx context.Context, vote domain.QuestionVote) (domain.QuestionVote, error) {
+	var previous domain.QuestionVote
+
+	err := r.db.Collection(domain.QuestionVoteCollectionName).
+		FindOneAndUpdate(ctx,
+			bson.M{"question_id": vote.QuestionID, "user_id": vote.UserID},
+			bson.M{
+				"$set": bson.M{"value": vote.Value, "updated_at": time.Now()},
+				"$setOnInsert": bson.M{
+					"_id":        vote.ID,
+					"reputation": 0,
+					"created_at": vote.CreatedAt,
+				},
+			},
+			options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before),
+		).Decode(&previous)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.QuestionVote{}, nil
+		}
+
+		return domain.QuestionVote{}, err
+	}
+
+	return previous, nil
+}
+
+// SetReputation records the reputation the vote gave the question author.
+func (r *questionVoteRepository) SetReputation(ctx context.Context, questionID, userID bson.ObjectID, reputation int) error {
+	_, err := r.db.Collection(domain.QuestionVoteCollectionName).
+		UpdateOne(ctx,
+			bson.M{"question_id": questionID, "user_id": userID},
+			bson.M{"$set": bson.M{"reputation": reputation}},
+		)
+
+	return err
+}
+
+func (r *questionVoteRepository) Delete(ctx context.Context, questionID, userID bson.ObjectID) (domain.QuestionVote, error) {
+	var previous domain.QuestionVote
+
+	err := r.db.Collection(domain.QuestionVoteCollectionName).
+		FindOneAndDelete(ctx, bson.M{"question_id": questionID, "user_id": userID}).Decode(&previous)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.QuestionVote{}, nil
+		}
+
+		return domain.QuestionVote{}, err
+	}
+
+	return previous, nil
+}
+
+func (r *questionVoteRepository) DeleteAllByQuestionID(ctx context.Context, questionID bson.ObjectID) error {
+	_, err := r.db.Collection(domain.QuestionVoteCollectionName).
+		DeleteMany(ctx, bson.M{"question_id": questionID})
+
+	return err
+}
@@ -2,8 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
 	"github.com/Closi-App/backend/internal/domain"
 	"github.com/Closi-App/backend/internal/repository"
+	"github.com/spf13/viper"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"time"
 )
@@ -15,29 +17,47 @@ type AnswerService interface {
 	Update(ctx context.Context, id, userID bson.ObjectID, input domain.AnswerUpdateInput) error
 	Delete(ctx context.Context, id, userID bson.ObjectID) error
 
-	AddLike(ctx context.Context, id bson.ObjectID) error
-	RemoveLike(ctx context.Context, id bson.ObjectID) error
-	Verify(ctx context.Context, id bson.ObjectID) error
+	AddLike(ctx context.Context, id, userID bson.ObjectID) error
+	RemoveLike(ctx context.Context, id, userID bson.ObjectID) error
+	Verify(ctx context.Context, id, userID bson.ObjectID) error
+
+	GetRevisions(ctx context.Context, id bson.ObjectID) ([]RevisionDetails, error)
+	Rollback(ctx context.Context, id, revisionID, userID bson.ObjectID) error
+
+	Restore(ctx context.Context, id, userID bson.ObjectID) error
+	Purge(ctx context.Context, id bson.ObjectID) error
 }
 
 type answerService struct {
 	*Service
 	repository      repository.AnswerRepository
+	likeRepository  repository.AnswerLikeRepository
 	questionService QuestionService
 	userService     UserService
+	revisionService RevisionService
+	eventService    EventService
+	restoreWindow   time.Duration
 }
 
 func NewAnswerService(
 	service *Service,
+	cfg *viper.Viper,
 	repository repository.AnswerRepository,
+	likeRepository repository.AnswerLikeRepository,
 	questionService QuestionService,
 	userService UserService,
+	revisionService RevisionService,
+	eventService EventService,
 ) AnswerService {
 	return &answerService{
 		Service:         service,
 		repository:      repository,
+		likeRepository:  likeRepository,
 		questionService: questionService,
 		userService:     userService,
+		revisionService: revisionService,
+		eventService:    eventService,
+		restoreWindow:   getDuration(cfg, "content.restore_window", domain.DefaultRestoreWindow),
 	}
 }
 
@@ -48,9 +68,14 @@ type AnswerCreateInput struct {
 }
 
 func (s *answerService) Create(ctx context.Context, input AnswerCreateInput) (bson.ObjectID, error) {
+	question, err := s.questionService.GetByID(ctx, input.QuestionID)
+	if err != nil {
+		return bson.ObjectID{}, err
+	}
+
 	id := bson.NewObjectID()
 
-	if err := s.repository.Create(ctx, domain.Answer{
+	if err = s.repository.Create(ctx, domain.Answer{
 		ID:         id,
 		Text:       input.Text,
 		Likes:      0,
@@ -63,18 +88,13 @@ func (s *answerService) Create(ctx context.Context, input AnswerCreateInput) (bs
 		return bson.ObjectID{}, err
 	}
 
-	question, err := s.questionService.GetByID(ctx, input.QuestionID)
-	if err != nil {
-		return bson.ObjectID{}, err
-	}
-
-	if err = s.userService.AdjustPoints(ctx, input.UserID, int(question.Points)); err != nil {
-		return bson.ObjectID{}, err
-	}
-
-	if err = s.userService.AdjustPoints(ctx, question.UserID, -1*int(question.Points)); err != nil {
-		return bson.ObjectID{}, err
-	}
+	s.eventService.Publish(ctx, domain.Event{
+		Type:       domain.AnswerCreatedEvent,
+		UserID:     question.UserID,
+		ActorID:    &input.UserID,
+		QuestionID: &question.ID,
+		AnswerID:   &id,
+	})
 
 	return id, nil
 }
@@ -88,21 +108,237 @@ func (s *answerService) GetByID(ctx context.Context, id bson.ObjectID) (domain.A
 }
 
 func (s *answerService) Update(ctx context.Context, id, userID bson.ObjectID, input domain.AnswerUpdateInput) error {
-	return s.repository.Update(ctx, id, userID, input)
+	answer, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err = s.userService.CheckOwnership(ctx, userID, answer.UserID); err != nil {
+		return err
+	}
+
+	if err = s.repository.Update(ctx, id, input); err != nil {
+		return err
+	}
+
+	return s.recordRevision(ctx, answer, userID, input, nil)
 }
 
 func (s *answerService) Delete(ctx context.Context, id, userID bson.ObjectID) error {
+	answer, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err = s.userService.CheckOwnership(ctx, userID, answer.UserID); err != nil {
+		return err
+	}
+
 	return s.repository.Delete(ctx, id, userID)
 }
 
-func (s *answerService) AddLike(ctx context.Context, id bson.ObjectID) error {
-	return s.repository.AddLike(ctx, id)
+func (s *answerService) AddLike(ctx context.Context, id, userID bson.ObjectID) error {
+	answer, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if answer.UserID == userID {
+		return domain.ErrAnswerOwnLike
+	}
+
+	// repeated likes of the same user are ignored, so they neither add up nor notify the author again
+	created, err := s.likeRepository.Create(ctx, domain.AnswerLike{
+		ID:        bson.NewObjectID(),
+		AnswerID:  id,
+		UserID:    userID,
+		CreatedAt: time.Now(),
+	})
+	if err != nil || !created {
+		return err
+	}
+
+	if err = s.repository.AddLike(ctx, id); err != nil {
+		return err
+	}
+
+	s.eventService.Publish(ctx, domain.Event{
+		Type:       domain.AnswerLikedEvent,
+		UserID:     answer.UserID,
+		ActorID:    &userID,
+		QuestionID: &answer.QuestionID,
+		AnswerID:   &id,
+	})
+
+	return nil
 }
 
-func (s *answerService) RemoveLike(ctx context.Context, id bson.ObjectID) error {
-	return s.repository.RemoveLike(ctx, id)
+func (s *answerService) RemoveLike(ctx context.Context, id, userID bson.ObjectID) error {
+	answer, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.likeRepository.Delete(ctx, id, userID)
+	if err != nil || !deleted {
+		return err
+	}
+
+	if err = s.repository.RemoveLike(ctx, id); err != nil {
+		return err
+	}
+
+	s.eventService.Publish(ctx, domain.Event{
+		Type:       domain.AnswerUnlikedEvent,
+		UserID:     answer.UserID,
+		ActorID:    &userID,
+		QuestionID: &answer.QuestionID,
+		AnswerID:   &id,
+	})
+
+	return nil
 }
 
-func (s *answerService) Verify(ctx context.Context, id bson.ObjectID) error {
-	return s.repository.Verify(ctx, id)
+func (s *answerService) Verify(ctx context.Context, id, userID bson.ObjectID) error {
+	answer, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	question, err := s.questionService.GetByID(ctx, answer.QuestionID)
+	if err != nil {
+		return err
+	}
+
+	if question.UserID != userID {
+		return domain.ErrForbidden
+	}
+
+	if err = s.questionService.SetAcceptedAnswer(ctx, question.ID, id); err != nil {
+		return err
+	}
+
+	if err = s.repository.Verify(ctx, id); err != nil {
+		return err
+	}
+
+	// release the points held by the question, unless the bounty has already expired
+	released, err := s.questionService.ReleaseBounty(ctx, question.ID)
+	if err != nil {
+		return err
+	}
+
+	var points int
+
+	if released {
+		points = int(question.Points)
+		// points of deleted users are dropped
+		if err = s.userService.AdjustPoints(ctx, answer.UserID, points); err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+			return err
+		}
+	}
+
+	s.eventService.Publish(ctx, domain.Event{
+		Type:       domain.AnswerAcceptedEvent,
+		UserID:     answer.UserID,
+		ActorID:    &userID,
+		QuestionID: &question.ID,
+		AnswerID:   &id,
+		Points:     points,
+	})
+
+	return nil
+}
+
+func (s *answerService) GetRevisions(ctx context.Context, id bson.ObjectID) ([]RevisionDetails, error) {
+	if _, err := s.repository.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.revisionService.GetAll(ctx, domain.AnswerTarget, id)
+}
+
+func (s *answerService) Rollback(ctx context.Context, id, revisionID, userID bson.ObjectID) error {
+	answer, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	revision, err := s.revisionService.GetByID(ctx, domain.AnswerTarget, id, revisionID)
+	if err != nil {
+		return err
+	}
+
+	input := domain.AnswerUpdateInput{
+		Text: &revision.Content.Text,
+	}
+
+	if err = s.repository.Update(ctx, id, input); err != nil {
+		return err
+	}
+
+	return s.recordRevision(ctx, answer, userID, input, &revision.ID)
+}
+
+func (s *answerService) Restore(ctx context.Context, id, userID bson.ObjectID) error {
+	answer, err := s.repository.GetDeletedByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// owners can restore only their own deletions, moderators can restore anything
+	if answer.UserID != userID || *answer.DeletedBy != userID {
+		user, err := s.userService.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		if !user.IsModerator() {
+			return domain.ErrForbidden
+		}
+	}
+
+	if time.Since(*answer.DeletedAt) > s.restoreWindow {
+		return domain.ErrRestoreWindowExpired
+	}
+
+	return s.repository.Restore(ctx, id)
+}
+
+func (s *answerService) Purge(ctx context.Context, id bson.ObjectID) error {
+	if err := s.revisionService.DeleteAllByTarget(ctx, domain.AnswerTarget, id); err != nil {
+		return err
+	}
+
+	if err := s.likeRepository.DeleteAllByAnswerID(ctx, id); err != nil {
+		return err
+	}
+
+	return s.repository.Purge(ctx, id)
+}
+
+func (s *answerService) recordRevision(
+	ctx context.Context,
+	answer domain.Answer,
+	userID bson.ObjectID,
+	input domain.AnswerUpdateInput,
+	rollbackOf *bson.ObjectID,
+) error {
+	previous := domain.NewAnswerRevisionContent(answer)
+	current := previous
+
+	if input.Text != nil {
+		current.Text = *input.Text
+	}
+
+	return s.revisionService.Record(ctx, RevisionRecordInput{
+		TargetType:        domain.AnswerTarget,
+		TargetID:          answer.ID,
+		UserID:            userID,
+		Previous:          previous,
+		Current:           current,
+		RollbackOf:        rollbackOf,
+		OriginalUserID:    answer.UserID,
+		OriginalCreatedAt: answer.CreatedAt,
+	})
 }
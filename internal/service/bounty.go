@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/Closi-App/backend/internal/domain"
+	"github.com/Closi-App/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"time"
+)
+
+// BountyService settles question bounties which expired without an accepted answer.
+type BountyService interface {
+	ResolveExpired(ctx context.Context) error
+	RemindExpiring(ctx context.Context) error
+}
+
+type bountyService struct {
+	*Service
+	questionRepository repository.QuestionRepository
+	answerRepository   repository.AnswerRepository
+	userService        UserService
+	emailService       EmailService
+}
+
+func NewBountyService(
+	service *Service,
+	questionRepository repository.QuestionRepository,
+	answerRepository repository.AnswerRepository,
+	userService UserService,
+	emailService EmailService,
+) BountyService {
+	return &bountyService{
+		Service:            service,
+		questionRepository: questionRepository,
+		answerRepository:   answerRepository,
+		userService:        userService,
+		emailService:       emailService,
+	}
+}
+
+func (s *bountyService) ResolveExpired(ctx context.Context) error {
+	var resolved int
+
+	for {
+		question, err := s.questionRepository.ClaimExpiredBounty(ctx, time.Now())
+		if err != nil {
+			if errors.Is(err, domain.ErrQuestionNotFound) {
+				break
+			}
+
+			return err
+		}
+
+		if err = s.resolve(ctx, question); err != nil {
+			s.log.Error().Err(err).Msgf("error resolving bounty of question (%s)", question.ID)
+		}
+
+		resolved++
+	}
+
+	if resolved > 0 {
+		s.log.Info().Msgf("resolved %d expired bounties", resolved)
+	}
+
+	return nil
+}
+
+// RemindExpiring emails the askers whose bounties expire soon without an accepted answer.
+func (s *bountyService) RemindExpiring(ctx context.Context) error {
+	for {
+		question, err := s.questionRepository.ClaimExpiringBounty(ctx, time.Now())
+		if err != nil {
+			if errors.Is(err, domain.ErrQuestionNotFound) {
+				return nil
+			}
+
+			return err
+		}
+
+		s.notify(ctx, question.UserID, domain.BountyExpiringEmail, question)
+	}
+}
+
+// resolve awards the bounty to the most liked answer or refunds it to the asker if there is none,
+// the asker's own answers never win it.
+func (s *bountyService) resolve(ctx context.Context, question domain.Question) error {
+	answer, err := s.answerRepository.GetMostLiked(ctx, question.ID, question.UserID, domain.BountyAutoAwardMinLikes)
+	if err != nil {
+		if !errors.Is(err, domain.ErrAnswerNotFound) {
+			return err
+		}
+
+		return s.refund(ctx, question)
+	}
+
+	if err = s.userService.AdjustPoints(ctx, answer.UserID, int(question.Points)); err != nil {
+		// the answer author has deleted the account, so the bounty goes back to the asker
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return s.refund(ctx, question)
+		}
+		return err
+	}
+
+	s.notify(ctx, question.UserID, domain.BountyAwardedEmail, question)
+	s.notify(ctx, answer.UserID, domain.BountyReceivedEmail, question)
+
+	return nil
+}
+
+func (s *bountyService) refund(ctx context.Context, question domain.Question) error {
+	if err := s.userService.AdjustPoints(ctx, question.UserID, int(question.Points)); err != nil {
+		return err
+	}
+
+	s.notify(ctx, question.UserID, domain.BountyRefundedEmail, question)
+
+	return nil
+}
+
+// notify emails the user about the bounty, failures don't affect the already moved points.
+func (s *bountyService) notify(ctx context.Context, userID bson.ObjectID, emailType domain.EmailType, question domain.Question) {
+	user, err := s.userService.GetByID(ctx, userID)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("error getting user (%s) to send %s email", userID, emailType)
+		return
+	}
+
+	if err = s.emailService.SendToUser(ctx, user, emailType, domain.BountyEmailData{
+		Name:          user.Name,
+		QuestionTitle: question.Title,
+		Points:        question.Points,
+	}); err != nil {
+		s.log.Error().Err(err).Msgf("error sending %s email to user (%s)", emailType, userID)
+	}
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"github.com/Closi-App/backend/internal/domain"
+	"github.com/Closi-App/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"time"
+)
+
+type CommentService interface {
+	Create(ctx context.Context, input CommentCreateInput) (bson.ObjectID, error)
+	GetAll(ctx context.Context, filter ...domain.CommentGetAllFilter) ([]domain.Comment, error)
+	GetByID(ctx context.Context, id bson.ObjectID) (domain.Comment, error)
+	Update(ctx context.Context, id, userID bson.ObjectID, input domain.CommentUpdateInput) error
+	Delete(ctx context.Context, id, userID bson.ObjectID) error
+
+	Hide(ctx context.Context, id bson.ObjectID) error
+	Unhide(ctx context.Context, id bson.ObjectID) error
+	DeleteAllByTarget(ctx context.Context, targetType domain.TargetType, targetID bson.ObjectID) error
+}
+
+type commentService struct {
+	*Service
+	repository        repository.CommentRepository
+	moderationService ModerationService
+	questionService   QuestionService
+	answerService     AnswerService
+	userService       UserService
+}
+
+func NewCommentService(
+	service *Service,
+	repository repository.CommentRepository,
+	moderationService ModerationService,
+	questionService QuestionService,
+	answerService AnswerService,
+	userService UserService,
+) CommentService {
+	return &commentService{
+		Service:           service,
+		repository:        repository,
+		moderationService: moderationService,
+		questionService:   questionService,
+		answerService:     answerService,
+		userService:       userService,
+	}
+}
+
+type CommentCreateInput struct {
+	Text       string
+	TargetType domain.TargetType
+	TargetID   bson.ObjectID
+	ParentID   *bson.ObjectID
+	UserID     bson.ObjectID
+}
+
+func (s *commentService) Create(ctx context.Context, input CommentCreateInput) (bson.ObjectID, error) {
+	if err := s.moderationService.Check(ctx, input.Text); err != nil {
+		return bson.ObjectID{}, err
+	}
+
+	if err := s.checkTarget(ctx, input.TargetType, input.TargetID); err != nil {
+		return bson.ObjectID{}, err
+	}
+
+	if input.ParentID != nil {
+		parent, err := s.repository.GetByID(ctx, *input.ParentID)
+		if err != nil {
+			return bson.ObjectID{}, err
+		}
+
+		if parent.TargetType != input.TargetType || parent.TargetID != input.TargetID {
+			return bson.ObjectID{}, domain.ErrCommentInvalidParent
+		}
+	}
+
+	id := bson.NewObjectID()
+
+	if err := s.repository.Create(ctx, domain.Comment{
+		ID:         id,
+		Text:       input.Text,
+		TargetType: input.TargetType,
+		TargetID:   input.TargetID,
+		ParentID:   input.ParentID,
+		UserID:     input.UserID,
+		IsHidden:   false,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}); err != nil {
+		return bson.ObjectID{}, err
+	}
+
+	return id, nil
+}
+
+func (s *commentService) GetAll(ctx context.Context, filter ...domain.CommentGetAllFilter) ([]domain.Comment, error) {
+	return s.repository.GetAll(ctx, filter...)
+}
+
+func (s *commentService) GetByID(ctx context.Context, id bson.ObjectID) (domain.Comment, error) {
+	return s.repository.GetByID(ctx, id)
+}
+
+func (s *commentService) Update(ctx context.Context, id, userID bson.ObjectID, input domain.CommentUpdateInput) error {
+	if input.Text != nil {
+		if err := s.moderationService.Check(ctx, *input.Text); err != nil {
+			return err
+		}
+	}
+
+	if err := s.checkOwnership(ctx, id, userID); err != nil {
+		return err
+	}
+
+	return s.repository.Update(ctx, id, input)
+}
+
+func (s *commentService) Delete(ctx context.Context, id, userID bson.ObjectID) error {
+	if err := s.checkOwnership(ctx, id, userID); err != nil {
+		return err
+	}
+
+	return s.repository.Delete(ctx, id)
+}
+
+func (s *commentService) Hide(ctx context.Context, id bson.ObjectID) error {
+	return s.repository.SetHidden(ctx, id, true)
+}
+
+func (s *commentService) Unhide(ctx context.Context, id bson.ObjectID) error {
+	return s.repository.SetHidden(ctx, id, false)
+}
+
+func (s *commentService) DeleteAllByTarget(ctx context.Context, targetType domain.TargetType, targetID bson.ObjectID) error {
+	return s.repository.DeleteAllByTarget(ctx, targetType, targetID)
+}
+
+func (s *commentService) checkTarget(ctx context.Context, targetType domain.TargetType, targetID bson.ObjectID) error {
+	switch targetType {
+	case domain.QuestionTarget:
+		_, err := s.questionService.GetByID(ctx, targetID)
+		return err
+	case domain.AnswerTarget:
+		_, err := s.answerService.GetByID(ctx, targetID)
+		return err
+	default:
+		return domain.ErrBadRequest
+	}
+}
+
+func (s *commentService) checkOwnership(ctx context.Context, id, userID bson.ObjectID) error {
+	comment, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.userService.CheckOwnership(ctx, userID, comment.UserID)
+}
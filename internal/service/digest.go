@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"github.com/Closi-App/backend/internal/domain"
+	"github.com/Closi-App/backend/internal/repository"
+	"time"
+)
+
+// DigestService sends periodic summaries of new questions, activity on the user content and points changes.
+type DigestService interface {
+	SendDue(ctx context.Context) error
+}
+
+type digestService struct {
+	*Service
+	userRepository         repository.UserRepository
+	questionRepository     repository.QuestionRepository
+	followRepository       repository.FollowRepository
+	notificationRepository repository.NotificationRepository
+	emailService           EmailService
+}
+
+func NewDigestService(
+	service *Service,
+	userRepository repository.UserRepository,
+	questionRepository repository.QuestionRepository,
+	followRepository repository.FollowRepository,
+	notificationRepository repository.NotificationRepository,
+	emailService EmailService,
+) DigestService {
+	return &digestService{
+		Service:                service,
+		userRepository:         userRepository,
+		questionRepository:     questionRepository,
+		followRepository:       followRepository,
+		notificationRepository: notificationRepository,
+		emailService:           emailService,
+	}
+}
+
+// SendDue sends the digests which are due, the rest of the users are handled by the next runs.
+func (s *digestService) SendDue(ctx context.Context) error {
+	now := time.Now()
+
+	users, err := s.userRepository.GetDigestDue(ctx, now, domain.DigestBatchSize)
+	if err != nil {
+		return err
+	}
+
+	var sent int
+
+	for _, user := range users {
+		ok, err := s.send(ctx, user, now)
+		if err != nil {
+			s.log.Error().Err(err).Msgf("error sending digest to user (%s)", user.ID)
+			continue
+		}
+
+		if ok {
+			sent++
+		}
+	}
+
+	if sent > 0 {
+		s.log.Info().Msgf("sent %d digests", sent)
+	}
+
+	return nil
+}
+
+// send schedules the next digest of the user and sends the current one, users who have just enabled digests
+// are only scheduled. It reports whether the digest was sent.
+func (s *digestService) send(ctx context.Context, user domain.User, now time.Time) (bool, error) {
+	frequency := user.Settings.EmailPreferences.Digest
+	nextAt := frequency.NextAt(now, user.Settings.Location())
+
+	digest := domain.UserDigest{
+		NextAt: &nextAt,
+		SentAt: &now,
+		Points: user.Points,
+	}
+
+	scheduling := user.Digest.NextAt == nil
+	if scheduling {
+		digest.SentAt = user.Digest.SentAt
+	}
+
+	claimed, err := s.userRepository.ClaimDigest(ctx, user.ID, user.Digest.NextAt, digest)
+	if err != nil || !claimed || scheduling {
+		return false, err
+	}
+
+	since := now.Add(-frequency.Period())
+	if user.Digest.SentAt != nil && user.Digest.SentAt.After(since) {
+		since = *user.Digest.SentAt
+	}
+
+	data, err := s.collect(ctx, user, since)
+	if err != nil {
+		return false, err
+	}
+
+	if data.IsEmpty() {
+		return false, nil
+	}
+
+	emailType := domain.DailyDigestEmail
+	if frequency == domain.WeeklyDigest {
+		emailType = domain.WeeklyDigestEmail
+	}
+
+	if err = s.emailService.SendToUser(ctx, user, emailType, data); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (s *digestService) collect(ctx context.Context, user domain.User, since time.Time) (domain.DigestEmailData, error) {
+	follows, err := s.followRepository.GetAllByUserID(ctx, user.ID)
+	if err != nil {
+		return domain.DigestEmailData{}, err
+	}
+
+	filter := domain.DigestQuestionsFilter{
+		CountryID:     user.Settings.CountryID,
+		ExcludeUserID: user.ID,
+		Since:         since,
+		Limit:         domain.DigestQuestionsLimit,
+	}
+
+	for _, follow := range follows {
+		if follow.TargetType == domain.TagFollow {
+			filter.TagIDs = append(filter.TagIDs, follow.TargetID)
+		}
+	}
+
+	questions, err := s.questionRepository.GetUnanswered(ctx, filter)
+	if err != nil {
+		return domain.DigestEmailData{}, err
+	}
+
+	activity, err := s.notificationRepository.CountByType(ctx, user.ID, since)
+	if err != nil {
+		return domain.DigestEmailData{}, err
+	}
+
+	return domain.DigestEmailData{
+		Name:            user.Name,
+		Weekly:          user.Settings.EmailPreferences.Digest == domain.WeeklyDigest,
+		Questions:       questions,
+		Answers:         activity[domain.AnswerCreatedEvent],
+		Likes:           activity[domain.AnswerLikedEvent],
+		AcceptedAnswers: activity[domain.AnswerAcceptedEvent],
+		PointsChange:    int(user.Points) - int(user.Digest.Points),
+		Points:          user.Points,
+	}, nil
+}
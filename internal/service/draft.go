@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/Closi-App/backend/internal/domain"
+	"github.com/Closi-App/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"time"
+)
+
+type QuestionDraftService interface {
+	Create(ctx context.Context, input QuestionDraftCreateInput) (bson.ObjectID, error)
+	GetAllByUserID(ctx context.Context, userID bson.ObjectID) ([]domain.QuestionDraft, error)
+	GetByID(ctx context.Context, id, userID bson.ObjectID) (domain.QuestionDraft, error)
+	Update(ctx context.Context, id, userID bson.ObjectID, input domain.QuestionDraftSaveInput) error
+	Delete(ctx context.Context, id, userID bson.ObjectID) error
+
+	Publish(ctx context.Context, id, userID bson.ObjectID) (bson.ObjectID, error)
+	Schedule(ctx context.Context, id, userID bson.ObjectID, publishAt time.Time) error
+	PublishScheduled(ctx context.Context) error
+}
+
+type questionDraftService struct {
+	*Service
+	repository      repository.QuestionDraftRepository
+	questionService QuestionService
+}
+
+func NewQuestionDraftService(
+	service *Service,
+	repository repository.QuestionDraftRepository,
+	questionService QuestionService,
+) QuestionDraftService {
+	return &questionDraftService{
+		Service:         service,
+		repository:      repository,
+		questionService: questionService,
+	}
+}
+
+type QuestionDraftCreateInput struct {
+	domain.QuestionDraftSaveInput
+	CountryID bson.ObjectID
+	UserID    bson.ObjectID
+}
+
+func (s *questionDraftService) Create(ctx context.Context, input QuestionDraftCreateInput) (bson.ObjectID, error) {
+	id := bson.NewObjectID()
+
+	if err := s.repository.Create(ctx, domain.QuestionDraft{
+		ID:             id,
+		Title:          input.Title,
+		Description:    input.Description,
+		AttachmentsURL: input.AttachmentsURL,
+		Tags:           input.Tags,
+		Points:         input.Points,
+		CountryID:      input.CountryID,
+		UserID:         input.UserID,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}); err != nil {
+		return bson.ObjectID{}, err
+	}
+
+	return id, nil
+}
+
+func (s *questionDraftService) GetAllByUserID(ctx context.Context, userID bson.ObjectID) ([]domain.QuestionDraft, error) {
+	return s.repository.GetAllByUserID(ctx, userID)
+}
+
+func (s *questionDraftService) GetByID(ctx context.Context, id, userID bson.ObjectID) (domain.QuestionDraft, error) {
+	return s.repository.GetByID(ctx, id, userID)
+}
+
+func (s *questionDraftService) Update(ctx context.Context, id, userID bson.ObjectID, input domain.QuestionDraftSaveInput) error {
+	return s.repository.Update(ctx, id, userID, input)
+}
+
+func (s *questionDraftService) Delete(ctx context.Context, id, userID bson.ObjectID) error {
+	return s.repository.Delete(ctx, id, userID)
+}
+
+// Publish creates the question from the draft and deletes the draft, the draft is restored if it fails.
+func (s *questionDraftService) Publish(ctx context.Context, id, userID bson.ObjectID) (bson.ObjectID, error) {
+	draft, err := s.repository.Claim(ctx, id, userID)
+	if err != nil {
+		return bson.ObjectID{}, err
+	}
+
+	questionID, err := s.publish(ctx, draft)
+	if err != nil {
+		s.restore(ctx, draft)
+		return bson.ObjectID{}, err
+	}
+
+	return questionID, nil
+}
+
+func (s *questionDraftService) Schedule(ctx context.Context, id, userID bson.ObjectID, publishAt time.Time) error {
+	if !publishAt.After(time.Now()) {
+		return domain.ErrDraftInvalidPublishTime
+	}
+
+	return s.repository.Schedule(ctx, id, userID, publishAt)
+}
+
+// PublishScheduled publishes all drafts which are due. Drafts which fail to publish
+// keep the error for the author and are not retried until scheduled again.
+func (s *questionDraftService) PublishScheduled(ctx context.Context) error {
+	for {
+		draft, err := s.repository.ClaimDue(ctx, time.Now())
+		if err != nil {
+			if errors.Is(err, domain.ErrDraftNotFound) {
+				return nil
+			}
+
+			return err
+		}
+
+		if _, err = s.publish(ctx, draft); err != nil {
+			s.log.Warn().Err(err).Msgf("error publishing scheduled draft (%s)", draft.ID)
+
+			// the error code is shown to the author, so internal errors are not exposed
+			publishError := domain.ErrInternalServerError.Code
+
+			var domainErr *domain.Error
+			if errors.As(err, &domainErr) {
+				publishError = domainErr.Code
+			}
+
+			draft.PublishAt = nil
+			draft.PublishError = publishError
+			s.restore(ctx, draft)
+		}
+	}
+}
+
+// publish goes through the same validation and points escrow as a question posted directly,
+// the draft must be claimed first.
+func (s *questionDraftService) publish(ctx context.Context, draft domain.QuestionDraft) (bson.ObjectID, error) {
+	questionID, err := s.questionService.Create(ctx, QuestionCreateInput{
+		Title:          draft.Title,
+		Description:    draft.Description,
+		AttachmentsURL: draft.AttachmentsURL,
+		Tags:           draft.Tags,
+		Points:         draft.Points,
+		CountryID:      draft.CountryID,
+		UserID:         draft.UserID,
+	})
+	if err != nil {
+		return bson.ObjectID{}, err
+	}
+
+	return questionID, nil
+}
+
+// restore puts back the claimed draft which failed to publish.
+func (s *questionDraftService) restore(ctx context.Context, draft domain.QuestionDraft) {
+	if err := s.repository.Create(ctx, draft); err != nil {
+		s.log.Error().Err(err).Msgf("error restoring draft (%s) which failed to publish", draft.ID)
+	}
+}
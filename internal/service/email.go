@@ -1,56 +1,278 @@
 package service
 
 import (
+	"context"
+	"crypto/subtle"
+	"errors"
 	"fmt"
 	"github.com/Closi-App/backend/internal/domain"
+	"github.com/Closi-App/backend/internal/repository"
 	"github.com/Closi-App/backend/internal/utils"
+	"github.com/Closi-App/backend/pkg/auth"
 	"github.com/Closi-App/backend/pkg/localizer"
 	"github.com/Closi-App/backend/pkg/smtp"
+	"github.com/spf13/viper"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"golang.org/x/text/language"
+	"io"
+	"net/url"
+	"strings"
+	"time"
 )
 
+// EmailService renders emails into the outbox, they are sent by the delivery job with retries.
 type EmailService interface {
-	Send(to string, emailType domain.EmailType, lang string, data interface{}) error
+	Send(ctx context.Context, to string, emailType domain.EmailType, lang string, data interface{}) error
+	SendToUser(ctx context.Context, user domain.User, emailType domain.EmailType, data interface{}) error
+	DeliverDue(ctx context.Context) error
+	GetAll(ctx context.Context, filter domain.EmailGetAllFilter) ([]domain.OutboxEmail, error)
+	Retry(ctx context.Context, id bson.ObjectID) error
+	Unsubscribe(ctx context.Context, token string) error
+	Preview(emailType domain.EmailType, lang string) (domain.EmailPreview, error)
+	SendPreview(to string, emailType domain.EmailType, lang string) error
+	HandleBounce(ctx context.Context, input domain.EmailBounceInput) error
+	HandleReport(ctx context.Context, report io.Reader) error
+	VerifyWebhookSecret(secret string) error
 }
 
 type emailService struct {
 	*Service
-	localizer  *localizer.Localizer
-	smtpSender smtp.Sender
+	repository     repository.EmailOutboxRepository
+	userRepository repository.UserRepository
+	eventService   EventService
+	templates      *emailTemplates
+	smtpSender     smtp.Sender
+	signer         auth.Signer
+	unsubscribeURL string
+	webhookSecret  string
 }
 
-func NewEmailService(service *Service, localizer *localizer.Localizer, smtpSender smtp.Sender) EmailService {
+func NewEmailService(
+	service *Service,
+	cfg *viper.Viper,
+	repository repository.EmailOutboxRepository,
+	userRepository repository.UserRepository,
+	eventService EventService,
+	localizer *localizer.Localizer,
+	smtpSender smtp.Sender,
+	signer auth.Signer,
+	appSupportedLanguages []language.Tag,
+) EmailService {
+	templates, err := newEmailTemplates(localizer, appSupportedLanguages, cfg.GetBool("email.hot_reload"))
+	if err != nil {
+		panic("error loading email templates: " + err.Error())
+	}
+
 	return &emailService{
-		Service:    service,
-		localizer:  localizer,
-		smtpSender: smtpSender,
+		Service:        service,
+		repository:     repository,
+		userRepository: userRepository,
+		eventService:   eventService,
+		templates:      templates,
+		smtpSender:     smtpSender,
+		signer:         signer,
+		unsubscribeURL: cfg.GetString("email.unsubscribe_url"),
+		webhookSecret:  cfg.GetString("email.webhook_secret"),
+	}
+}
+
+// Send renders the email and puts it to the outbox.
+func (s *emailService) Send(ctx context.Context, to string, emailType domain.EmailType, lang string, data interface{}) error {
+	return s.enqueue(ctx, to, emailType, lang, data, nil)
+}
+
+// SendToUser sends the email in the user language, notification emails are skipped if the user turned off their category
+// and have one-click unsubscribe links otherwise. Nothing is sent to the emails which bounced.
+func (s *emailService) SendToUser(ctx context.Context, user domain.User, emailType domain.EmailType, data interface{}) error {
+	if user.EmailBounce != nil {
+		return nil
+	}
+
+	category, ok := emailType.Category()
+	if !ok {
+		return s.enqueue(ctx, user.Email, emailType, user.Settings.Language, data, nil)
 	}
+
+	if !user.Settings.EmailPreferences.Allows(category) {
+		return nil
+	}
+
+	return s.enqueue(ctx, user.Email, emailType, user.Settings.Language, data, s.unsubscribeHeaders(user.ID, category))
 }
 
-func (s *emailService) Send(to string, emailType domain.EmailType, lang string, data interface{}) error {
+// unsubscribeHeaders returns List-Unsubscribe headers for one-click unsubscribing (RFC 8058) from the category.
+func (s *emailService) unsubscribeHeaders(userID bson.ObjectID, category domain.EmailCategory) map[string][]string {
+	if s.unsubscribeURL == "" {
+		return nil
+	}
+
+	token := s.signer.Sign(domain.UnsubscribeValue(userID, category))
+	link := s.unsubscribeURL + "?" + url.Values{"token": {token}}.Encode()
+
+	return map[string][]string{
+		"List-Unsubscribe":      {"<" + link + ">"},
+		"List-Unsubscribe-Post": {"List-Unsubscribe=One-Click"},
+	}
+}
+
+// Unsubscribe turns off the email category from the signed unsubscribe link token.
+func (s *emailService) Unsubscribe(ctx context.Context, token string) error {
+	value, err := s.signer.Verify(token)
+	if err != nil {
+		return domain.ErrEmailInvalidUnsubscribeToken
+	}
+
+	userID, category, err := domain.ParseUnsubscribeValue(value)
+	if err != nil {
+		return err
+	}
+
+	preferences := domain.UnsubscribeInput(category)
+
+	return s.userRepository.UpdateSettings(ctx, userID, domain.UserSettingsUpdateInput{
+		EmailPreferences: &preferences,
+	})
+}
+
+func (s *emailService) enqueue(
+	ctx context.Context,
+	to string,
+	emailType domain.EmailType,
+	lang string,
+	data interface{},
+	headers map[string][]string,
+) error {
 	langTag, err := utils.ParseLanguage(lang)
 	if err != nil {
 		return err
 	}
 
-	l := s.localizer.SetLanguage(langTag)
+	preview, err := s.render(emailType, langTag, data)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	return s.repository.Create(ctx, domain.OutboxEmail{
+		ID:            bson.NewObjectID(),
+		To:            to,
+		Type:          emailType,
+		Subject:       preview.Subject,
+		Body:          preview.HTML,
+		TextBody:      preview.Text,
+		Headers:       headers,
+		Status:        domain.PendingEmailStatus,
+		Attempts:      0,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	})
+}
+
+// render renders the subject, the HTML body and its plain text alternative.
+func (s *emailService) render(emailType domain.EmailType, lang language.Tag, data interface{}) (domain.EmailPreview, error) {
+	subject, body, err := s.templates.render(emailType, lang, data)
+	if err != nil {
+		return domain.EmailPreview{}, err
+	}
+
+	text, err := utils.HTMLToText(body.String())
+	if err != nil {
+		return domain.EmailPreview{}, err
+	}
 
-	// TODO: think about caching templates paths
-	templatePath := l.Translate(
-		fmt.Sprintf("emails.%s.template_path", emailType.String()),
-	)
-	subject := l.Translate(
-		fmt.Sprintf("emails.%s.subject", emailType.String()),
-	)
+	return domain.EmailPreview{
+		Type:     emailType,
+		Language: lang.String(),
+		Subject:  subject,
+		HTML:     body.String(),
+		Text:     text,
+	}, nil
+}
+
+// Preview renders the email with sample data in one of the supported languages.
+func (s *emailService) Preview(emailType domain.EmailType, lang string) (domain.EmailPreview, error) {
+	langTag, err := utils.ParseLanguage(lang)
+	if err != nil || !s.templates.supports(langTag) {
+		return domain.EmailPreview{}, domain.ErrBadRequest
+	}
 
-	body, err := utils.ParseHTMLTemplates(data, "./templates/emails/layout.html", templatePath)
+	return s.render(emailType, langTag, emailType.SampleData())
+}
+
+// SendPreview sends the email preview right away bypassing the outbox, so that the result is known immediately.
+func (s *emailService) SendPreview(to string, emailType domain.EmailType, lang string) error {
+	preview, err := s.Preview(emailType, lang)
 	if err != nil {
 		return err
 	}
 
+	return s.send(domain.OutboxEmail{
+		To:       to,
+		Subject:  preview.Subject,
+		Body:     preview.HTML,
+		TextBody: preview.Text,
+	})
+}
+
+// DeliverDue sends the outbox emails which are due, failed ones are retried with exponential backoff.
+func (s *emailService) DeliverDue(ctx context.Context) error {
+	var sent, failed int
+
+	for range domain.EmailDeliveryBatchSize {
+		email, err := s.repository.ClaimDue(ctx, time.Now())
+		if err != nil {
+			if errors.Is(err, domain.ErrEmailNotFound) {
+				break
+			}
+
+			return err
+		}
+
+		ok, err := s.deliver(ctx, email)
+		if err != nil {
+			s.log.Error().Err(err).Msgf("error recording delivery of email (%s)", email.ID)
+		}
+
+		if ok {
+			sent++
+		} else {
+			failed++
+		}
+	}
+
+	if sent > 0 || failed > 0 {
+		s.log.Info().Msgf("sent %d emails, %d failed", sent, failed)
+	}
+
+	return nil
+}
+
+// deliver sends the email, records the result and reports whether the email was sent.
+func (s *emailService) deliver(ctx context.Context, email domain.OutboxEmail) (bool, error) {
+	err := s.send(email)
+	if err == nil {
+		return true, s.repository.MarkSent(ctx, email.ID)
+	}
+
+	attempts := email.Attempts + 1
+
+	if attempts >= domain.EmailMaxAttempts {
+		s.log.Error().Err(err).Msgf("giving up sending %s email (%s) after %d attempts", email.Type, email.ID, attempts)
+	} else {
+		s.log.Warn().Err(err).Msgf("error sending %s email (%s), attempt %d", email.Type, email.ID, attempts)
+	}
+
+	return false, s.repository.MarkFailed(ctx, email.ID, attempts, time.Now().Add(domain.EmailBackoff(attempts)), err.Error())
+}
+
+func (s *emailService) send(email domain.OutboxEmail) error {
 	return s.smtpSender.Send(smtp.SendInput{
-		To:      []string{to},
-		Subject: subject,
-		Body:    body.String(),
+		To:       []string{email.To},
+		Subject:  email.Subject,
+		Body:     email.Body,
+		TextBody: email.TextBody,
+		Headers:  email.Headers,
 		EmbeddedFiles: []smtp.EmbeddedFile{
 			{
 				Path: "logo.png",
@@ -59,3 +281,101 @@ func (s *emailService) Send(to string, emailType domain.EmailType, lang string,
 		ContentType: smtp.HTMLContentType,
 	})
 }
+
+func (s *emailService) GetAll(ctx context.Context, filter domain.EmailGetAllFilter) ([]domain.OutboxEmail, error) {
+	filter.Limit = domain.ClampEmailLimit(filter.Limit)
+
+	return s.repository.GetAll(ctx, filter)
+}
+
+func (s *emailService) Retry(ctx context.Context, id bson.ObjectID) error {
+	return s.repository.Retry(ctx, id)
+}
+
+// VerifyWebhookSecret returns domain.ErrUnauthorized unless the secret matches email.webhook_secret,
+// the webhooks are disabled if it's not set.
+func (s *emailService) VerifyWebhookSecret(secret string) error {
+	if s.webhookSecret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(s.webhookSecret)) != 1 {
+		return domain.ErrUnauthorized
+	}
+
+	return nil
+}
+
+// HandleBounce marks the email as undeliverable for hard bounces and complaints, pending emails to it become dead letters
+// and the user is notified to update their email. Soft bounces are left to the outbox retries.
+func (s *emailService) HandleBounce(ctx context.Context, input domain.EmailBounceInput) error {
+	// providers may change the case of the recipient, the emails are matched ignoring it
+	input.Email = strings.ToLower(strings.TrimSpace(input.Email))
+
+	if !input.Type.IsPermanent() {
+		s.log.Info().Msgf("%s for %s: %s", input.Type, input.Email, input.Reason)
+		return nil
+	}
+
+	failed, err := s.repository.FailPending(ctx, input.Email, fmt.Sprintf("%s: %s", input.Type, input.Reason))
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepository.SetEmailBounce(ctx, input.Email, domain.EmailBounce{
+		Type:      input.Type,
+		Reason:    input.Reason,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	s.log.Info().Msgf("email of user (%s) marked undeliverable after %s, %d pending emails failed", user.ID, input.Type, failed)
+
+	s.eventService.Publish(ctx, domain.Event{
+		Type:   domain.EmailUndeliverableEvent,
+		UserID: user.ID,
+	})
+
+	return nil
+}
+
+// HandleReport handles the bounces and complaints of a delivery status notification or a feedback report message.
+func (s *emailService) HandleReport(ctx context.Context, report io.Reader) error {
+	reports, err := smtp.ParseReport(report)
+	if err != nil {
+		return domain.ErrBadRequest
+	}
+
+	for _, r := range reports {
+		if r.Recipient == "" {
+			continue
+		}
+
+		bounceType := domain.SoftEmailBounce
+		switch {
+		case r.Complaint:
+			bounceType = domain.EmailComplaint
+		case r.IsPermanent():
+			bounceType = domain.HardEmailBounce
+		case r.Action != "failed":
+			continue
+		}
+
+		reason := r.Diagnostic
+		if reason == "" {
+			reason = r.Status
+		}
+
+		if err = s.HandleBounce(ctx, domain.EmailBounceInput{
+			Email:  r.Recipient,
+			Type:   bounceType,
+			Reason: reason,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
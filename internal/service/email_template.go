@@ -0,0 +1,118 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/Closi-App/backend/internal/domain"
+	"github.com/Closi-App/backend/internal/utils"
+	"github.com/Closi-App/backend/pkg/localizer"
+	"golang.org/x/text/language"
+	"html/template"
+)
+
+const emailLayoutPath = "./templates/emails/layout.html"
+
+type emailTemplate struct {
+	subject  string
+	path     string
+	template *template.Template
+}
+
+// emailTemplates keeps the email templates of all supported languages compiled with the layout.
+// With hot reload the template files are parsed again on every render, so they can be edited without restarting.
+type emailTemplates struct {
+	templates map[language.Tag]map[domain.EmailType]emailTemplate
+	languages []language.Tag
+	matcher   language.Matcher
+	hotReload bool
+}
+
+// newEmailTemplates compiles the templates of every email type in every language,
+// an error is returned if any locale misses the email subject, the template path or the template file.
+func newEmailTemplates(localizer *localizer.Localizer, languages []language.Tag, hotReload bool) (*emailTemplates, error) {
+	if len(languages) == 0 {
+		return nil, fmt.Errorf("no supported languages")
+	}
+
+	templates := make(map[language.Tag]map[domain.EmailType]emailTemplate, len(languages))
+
+	for _, lang := range languages {
+		l := localizer.SetLanguage(lang)
+		templates[lang] = make(map[domain.EmailType]emailTemplate, len(domain.EmailTypes))
+
+		for _, emailType := range domain.EmailTypes {
+			subject, err := l.Localize(fmt.Sprintf("emails.%s.subject", emailType.String()))
+			if err != nil {
+				return nil, fmt.Errorf("error loading %s email subject: %w", emailType, err)
+			}
+
+			path, err := l.Localize(fmt.Sprintf("emails.%s.template_path", emailType.String()))
+			if err != nil {
+				return nil, fmt.Errorf("error loading %s email template path: %w", emailType, err)
+			}
+
+			t, err := compileEmailTemplate(path)
+			if err != nil {
+				return nil, fmt.Errorf("error compiling %s email template in %s locale: %w", emailType, lang, err)
+			}
+
+			templates[lang][emailType] = emailTemplate{
+				subject:  subject,
+				path:     path,
+				template: t,
+			}
+		}
+	}
+
+	return &emailTemplates{
+		templates: templates,
+		languages: languages,
+		matcher:   language.NewMatcher(languages),
+		hotReload: hotReload,
+	}, nil
+}
+
+func compileEmailTemplate(path string) (*template.Template, error) {
+	t, err := utils.CompileHTMLTemplates(emailLayoutPath, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Lookup("content") == nil {
+		return nil, fmt.Errorf("template %s doesn't define content", path)
+	}
+
+	return t, nil
+}
+
+// supports reports whether the templates are compiled for exactly this language.
+func (t *emailTemplates) supports(lang language.Tag) bool {
+	_, ok := t.templates[lang]
+	return ok
+}
+
+// render returns the subject and the HTML body of the email in the closest supported language.
+func (t *emailTemplates) render(emailType domain.EmailType, lang language.Tag, data interface{}) (string, bytes.Buffer, error) {
+	_, i, _ := t.matcher.Match(lang)
+
+	email, ok := t.templates[t.languages[i]][emailType]
+	if !ok {
+		return "", bytes.Buffer{}, fmt.Errorf("unknown email type: %s", emailType)
+	}
+
+	if t.hotReload {
+		compiled, err := compileEmailTemplate(email.path)
+		if err != nil {
+			return "", bytes.Buffer{}, err
+		}
+
+		email.template = compiled
+	}
+
+	body, err := utils.ExecuteHTMLTemplate(email.template, data)
+	if err != nil {
+		return "", bytes.Buffer{}, err
+	}
+
+	return email.subject, body, nil
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"github.com/Closi-App/backend/internal/domain"
+	"sync"
+	"time"
+)
+
+type EventHandler func(ctx context.Context, event domain.Event) error
+
+// EventService dispatches domain events to the subscribed handlers. Events are side effects of already
+// completed actions, so handler errors are only logged.
+type EventService interface {
+	Publish(ctx context.Context, event domain.Event)
+	Subscribe(handler EventHandler)
+}
+
+type eventService struct {
+	*Service
+	mu       sync.RWMutex
+	handlers []EventHandler
+}
+
+func NewEventService(service *Service) EventService {
+	return &eventService{
+		Service: service,
+	}
+}
+
+func (s *eventService) Publish(ctx context.Context, event domain.Event) {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	s.mu.RLock()
+	handlers := s.handlers
+	s.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			s.log.Error().Err(err).Msgf("error handling %s event for user (%s)", event.Type, event.UserID)
+		}
+	}
+}
+
+func (s *eventService) Subscribe(handler EventHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers = append(s.handlers, handler)
+}
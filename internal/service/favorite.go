@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"github.com/Closi-App/backend/internal/domain"
+	"github.com/Closi-App/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"time"
+)
+
+type FavoriteService interface {
+	Add(ctx context.Context, userID, questionID bson.ObjectID) error
+	GetAll(ctx context.Context, filter domain.FavoriteGetAllFilter) ([]domain.FavoriteQuestion, error)
+	Remove(ctx context.Context, userID, questionID bson.ObjectID) error
+	DeleteAllByQuestionID(ctx context.Context, questionID bson.ObjectID) error
+}
+
+type favoriteService struct {
+	*Service
+	repository         repository.FavoriteRepository
+	questionRepository repository.QuestionRepository
+}
+
+func NewFavoriteService(
+	service *Service,
+	repository repository.FavoriteRepository,
+	questionRepository repository.QuestionRepository,
+) FavoriteService {
+	return &favoriteService{
+		Service:            service,
+		repository:         repository,
+		questionRepository: questionRepository,
+	}
+}
+
+func (s *favoriteService) Add(ctx context.Context, userID, questionID bson.ObjectID) error {
+	if _, err := s.questionRepository.GetByID(ctx, questionID); err != nil {
+		return err
+	}
+
+	added, err := s.repository.Add(ctx, domain.Favorite{
+		ID:         bson.NewObjectID(),
+		UserID:     userID,
+		QuestionID: questionID,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if added {
+		return s.questionRepository.AdjustFavorites(ctx, questionID, 1)
+	}
+
+	return nil
+}
+
+func (s *favoriteService) GetAll(ctx context.Context, filter domain.FavoriteGetAllFilter) ([]domain.FavoriteQuestion, error) {
+	filter.Page = domain.ClampFavoritePage(filter.Page)
+	filter.Limit = domain.ClampFavoriteLimit(filter.Limit)
+
+	return s.repository.GetAll(ctx, filter)
+}
+
+func (s *favoriteService) Remove(ctx context.Context, userID, questionID bson.ObjectID) error {
+	removed, err := s.repository.Remove(ctx, userID, questionID)
+	if err != nil {
+		return err
+	}
+
+	if removed {
+		return s.questionRepository.AdjustFavorites(ctx, questionID, -1)
+	}
+
+	return nil
+}
+
+func (s *favoriteService) DeleteAllByQuestionID(ctx context.Context, questionID bson.ObjectID) error {
+	return s.repository.DeleteAllByQuestionID(ctx, questionID)
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"github.com/Closi-App/backend/internal/domain"
+	"github.com/Closi-App/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"slices"
+)
+
+// FeedService builds a personalized feed of new content from the tags, users and questions the user follows.
+type FeedService interface {
+	Get(ctx context.Context, userID bson.ObjectID, cursor string, limit int) (domain.Feed, error)
+}
+
+type feedService struct {
+	*Service
+	followRepository   repository.FollowRepository
+	questionRepository repository.QuestionRepository
+	answerRepository   repository.AnswerRepository
+}
+
+func NewFeedService(
+	service *Service,
+	followRepository repository.FollowRepository,
+	questionRepository repository.QuestionRepository,
+	answerRepository repository.AnswerRepository,
+) FeedService {
+	return &feedService{
+		Service:            service,
+		followRepository:   followRepository,
+		questionRepository: questionRepository,
+		answerRepository:   answerRepository,
+	}
+}
+
+func (s *feedService) Get(ctx context.Context, userID bson.ObjectID, cursor string, limit int) (domain.Feed, error) {
+	filter := domain.FeedFilter{
+		ExcludeUserID: userID,
+		Limit:         domain.ClampFeedLimit(limit),
+	}
+
+	if cursor != "" {
+		before, err := domain.ParseFeedCursor(cursor)
+		if err != nil {
+			return domain.Feed{}, err
+		}
+		filter.Before = &before
+	}
+
+	follows, err := s.followRepository.GetAllByUserID(ctx, userID)
+	if err != nil {
+		return domain.Feed{}, err
+	}
+
+	for _, follow := range follows {
+		switch follow.TargetType {
+		case domain.UserFollow:
+			filter.UserIDs = append(filter.UserIDs, follow.TargetID)
+		case domain.TagFollow:
+			filter.TagIDs = append(filter.TagIDs, follow.TargetID)
+		case domain.QuestionFollow:
+			filter.QuestionIDs = append(filter.QuestionIDs, follow.TargetID)
+		}
+	}
+
+	// both sources return at most a page of items after the cursor, so merging them gives the exact page
+	questions, err := s.questionRepository.GetFeed(ctx, filter)
+	if err != nil {
+		return domain.Feed{}, err
+	}
+
+	answers, err := s.answerRepository.GetFeed(ctx, filter)
+	if err != nil {
+		return domain.Feed{}, err
+	}
+
+	items := make([]domain.FeedItem, 0, len(questions)+len(answers))
+
+	for i := range questions {
+		items = append(items, domain.FeedItem{
+			Type:      domain.QuestionFeedItem,
+			Question:  &questions[i],
+			CreatedAt: questions[i].CreatedAt,
+		})
+	}
+	for i := range answers {
+		items = append(items, domain.FeedItem{
+			Type:      domain.AnswerFeedItem,
+			Answer:    &answers[i],
+			CreatedAt: answers[i].CreatedAt,
+		})
+	}
+
+	slices.SortFunc(items, func(a, b domain.FeedItem) int {
+		return b.Cursor().Compare(a.Cursor())
+	})
+
+	if len(items) > filter.Limit {
+		items = items[:filter.Limit]
+	}
+
+	feed := domain.Feed{Items: items}
+
+	if len(items) == filter.Limit {
+		feed.NextCursor = items[len(items)-1].Cursor().String()
+	}
+
+	return feed, nil
+}
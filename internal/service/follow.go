@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"github.com/Closi-App/backend/internal/domain"
+	"github.com/Closi-App/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"time"
+)
+
+type FollowService interface {
+	Follow(ctx context.Context, userID bson.ObjectID, targetType domain.FollowTarget, targetID bson.ObjectID) error
+	GetAllByUserID(ctx context.Context, userID bson.ObjectID) ([]domain.Follow, error)
+	Unfollow(ctx context.Context, userID bson.ObjectID, targetType domain.FollowTarget, targetID bson.ObjectID) error
+	DeleteAllByTarget(ctx context.Context, targetType domain.FollowTarget, targetID bson.ObjectID) error
+}
+
+type followService struct {
+	*Service
+	repository      repository.FollowRepository
+	userService     UserService
+	tagService      TagService
+	questionService QuestionService
+}
+
+func NewFollowService(
+	service *Service,
+	repository repository.FollowRepository,
+	userService UserService,
+	tagService TagService,
+	questionService QuestionService,
+) FollowService {
+	return &followService{
+		Service:         service,
+		repository:      repository,
+		userService:     userService,
+		tagService:      tagService,
+		questionService: questionService,
+	}
+}
+
+func (s *followService) Follow(ctx context.Context, userID bson.ObjectID, targetType domain.FollowTarget, targetID bson.ObjectID) error {
+	if err := s.checkTarget(ctx, userID, targetType, targetID); err != nil {
+		return err
+	}
+
+	return s.repository.Create(ctx, domain.Follow{
+		ID:         bson.NewObjectID(),
+		UserID:     userID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		CreatedAt:  time.Now(),
+	})
+}
+
+func (s *followService) GetAllByUserID(ctx context.Context, userID bson.ObjectID) ([]domain.Follow, error) {
+	return s.repository.GetAllByUserID(ctx, userID)
+}
+
+func (s *followService) Unfollow(ctx context.Context, userID bson.ObjectID, targetType domain.FollowTarget, targetID bson.ObjectID) error {
+	return s.repository.Delete(ctx, userID, targetType, targetID)
+}
+
+func (s *followService) DeleteAllByTarget(ctx context.Context, targetType domain.FollowTarget, targetID bson.ObjectID) error {
+	return s.repository.DeleteAllByTarget(ctx, targetType, targetID)
+}
+
+// checkTarget checks that the followed entity exists.
+func (s *followService) checkTarget(ctx context.Context, userID bson.ObjectID, targetType domain.FollowTarget, targetID bson.ObjectID) error {
+	var err error
+
+	switch targetType {
+	case domain.UserFollow:
+		if targetID == userID {
+			return domain.ErrFollowSelf
+		}
+		_, err = s.userService.GetByID(ctx, targetID)
+	case domain.TagFollow:
+		_, err = s.tagService.GetByID(ctx, targetID)
+	case domain.QuestionFollow:
+		_, err = s.questionService.GetByID(ctx, targetID)
+	default:
+		return domain.ErrFollowInvalidTarget
+	}
+
+	return err
+}
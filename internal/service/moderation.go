@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"github.com/Closi-App/backend/internal/domain"
+	"github.com/spf13/viper"
+	"strings"
+	"unicode/utf8"
+)
+
+// ModerationHook inspects user generated text before it is saved and returns an error to reject it.
+type ModerationHook func(ctx context.Context, text string) error
+
+type ModerationService interface {
+	Check(ctx context.Context, text string) error
+	RegisterHook(hook ModerationHook)
+}
+
+type moderationService struct {
+	*Service
+	hooks []ModerationHook
+}
+
+func NewModerationService(service *Service, cfg *viper.Viper) ModerationService {
+	s := &moderationService{
+		Service: service,
+	}
+
+	maxTextLength := cfg.GetInt("moderation.max_text_length")
+	if maxTextLength == 0 {
+		maxTextLength = domain.CommentMaxLength
+	}
+
+	s.RegisterHook(maxLengthModerationHook(maxTextLength))
+	s.RegisterHook(bannedWordsModerationHook(cfg.GetStringSlice("moderation.banned_words")))
+
+	return s
+}
+
+func (s *moderationService) Check(ctx context.Context, text string) error {
+	for _, hook := range s.hooks {
+		if err := hook(ctx, text); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *moderationService) RegisterHook(hook ModerationHook) {
+	s.hooks = append(s.hooks, hook)
+}
+
+func maxLengthModerationHook(maxLength int) ModerationHook {
+	return func(_ context.Context, text string) error {
+		if strings.TrimSpace(text) == "" {
+			return domain.ErrBadRequest
+		}
+		if utf8.RuneCountInString(text) > maxLength {
+			return domain.ErrCommentRejected
+		}
+
+		return nil
+	}
+}
+
+func bannedWordsModerationHook(bannedWords []string) ModerationHook {
+	return func(_ context.Context, text string) error {
+		lowerText := strings.ToLower(text)
+
+		for _, word := range bannedWords {
+			if word != "" && strings.Contains(lowerText, strings.ToLower(word)) {
+				return domain.ErrCommentRejected
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/Closi-App/backend/internal/domain"
+	"github.com/Closi-App/backend/internal/repository"
+	"github.com/Closi-App/backend/internal/utils"
+	"github.com/Closi-App/backend/pkg/localizer"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// NotificationService keeps the user inbox, notifications are created from events.
+type NotificationService interface {
+	GetAll(ctx context.Context, filter domain.NotificationGetAllFilter, lang string) ([]NotificationDetails, error)
+	CountUnread(ctx context.Context, userID bson.ObjectID) (int64, error)
+	MarkRead(ctx context.Context, id, userID bson.ObjectID) error
+	MarkAllRead(ctx context.Context, userID bson.ObjectID) error
+}
+
+type notificationService struct {
+	*Service
+	repository         repository.NotificationRepository
+	questionRepository repository.QuestionRepository
+	userService        UserService
+	emailService       EmailService
+	eventService       EventService
+	localizer          *localizer.Localizer
+}
+
+func NewNotificationService(
+	service *Service,
+	localizer *localizer.Localizer,
+	repository repository.NotificationRepository,
+	questionRepository repository.QuestionRepository,
+	userService UserService,
+	emailService EmailService,
+	eventService EventService,
+) NotificationService {
+	s := &notificationService{
+		Service:            service,
+		repository:         repository,
+		questionRepository: questionRepository,
+		userService:        userService,
+		emailService:       emailService,
+		eventService:       eventService,
+		localizer:          localizer,
+	}
+
+	eventService.Subscribe(s.handleEvent)
+
+	return s
+}
+
+type NotificationDetails struct {
+	domain.Notification
+	Text string `json:"text"`
+}
+
+func (s *notificationService) GetAll(ctx context.Context, filter domain.NotificationGetAllFilter, lang string) ([]NotificationDetails, error) {
+	filter.Limit = domain.ClampNotificationLimit(filter.Limit)
+
+	notifications, err := s.repository.GetAll(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	langTag, err := utils.ParseLanguage(lang)
+	if err != nil {
+		return nil, err
+	}
+
+	l := s.localizer.SetLanguage(langTag)
+
+	details := make([]NotificationDetails, len(notifications))
+	for i, notification := range notifications {
+		details[i] = NotificationDetails{
+			Notification: notification,
+			Text:         l.Translate(fmt.Sprintf("notifications.%s", notification.Type), notification.Params),
+		}
+	}
+
+	return details, nil
+}
+
+func (s *notificationService) CountUnread(ctx context.Context, userID bson.ObjectID) (int64, error) {
+	return s.repository.CountUnread(ctx, userID)
+}
+
+func (s *notificationService) MarkRead(ctx context.Context, id, userID bson.ObjectID) error {
+	return s.repository.MarkRead(ctx, id, userID)
+}
+
+func (s *notificationService) MarkAllRead(ctx context.Context, userID bson.ObjectID) error {
+	return s.repository.MarkAllRead(ctx, userID)
+}
+
+// handleEvent creates a notification for the user concerned by the event, users aren't notified about their own actions.
+func (s *notificationService) handleEvent(ctx context.Context, event domain.Event) error {
+	switch event.Type {
+	case domain.AnswerCreatedEvent, domain.AnswerLikedEvent, domain.AnswerAcceptedEvent, domain.ReferralJoinedEvent,
+		domain.EmailUndeliverableEvent:
+	default:
+		return nil
+	}
+
+	if event.ActorID != nil && *event.ActorID == event.UserID {
+		return nil
+	}
+
+	params := domain.NotificationParams{
+		Points: event.Points,
+	}
+
+	if event.ActorID != nil {
+		actor, err := s.userService.GetByID(ctx, *event.ActorID)
+		if err != nil {
+			return err
+		}
+		params.ActorName = actor.Name
+	}
+
+	if event.QuestionID != nil {
+		question, err := s.questionRepository.GetByID(ctx, *event.QuestionID)
+		if err != nil {
+			return err
+		}
+		params.QuestionTitle = question.Title
+	}
+
+	id := bson.NewObjectID()
+
+	if err := s.repository.Create(ctx, domain.Notification{
+		ID:         id,
+		UserID:     event.UserID,
+		Type:       event.Type,
+		ActorID:    event.ActorID,
+		QuestionID: event.QuestionID,
+		AnswerID:   event.AnswerID,
+		Params:     params,
+		IsRead:     false,
+		CreatedAt:  event.CreatedAt,
+	}); err != nil {
+		return err
+	}
+
+	s.eventService.Publish(ctx, domain.Event{
+		Type:           domain.NotificationCreatedEvent,
+		UserID:         event.UserID,
+		NotificationID: &id,
+	})
+
+	s.sendEmail(ctx, event, params)
+
+	return nil
+}
+
+// sendEmail emails the user about the event if it has a notification email, failures don't affect the notification.
+func (s *notificationService) sendEmail(ctx context.Context, event domain.Event, params domain.NotificationParams) {
+	var emailType domain.EmailType
+
+	switch event.Type {
+	case domain.AnswerCreatedEvent:
+		emailType = domain.NewAnswerEmail
+	case domain.AnswerAcceptedEvent:
+		emailType = domain.AnswerAcceptedEmail
+	default:
+		return
+	}
+
+	user, err := s.userService.GetByID(ctx, event.UserID)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("error getting user (%s) to send %s email", event.UserID, emailType)
+		return
+	}
+
+	if err = s.emailService.SendToUser(ctx, user, emailType, domain.AnswerEmailData{
+		Name:          user.Name,
+		ActorName:     params.ActorName,
+		QuestionTitle: params.QuestionTitle,
+		Points:        params.Points,
+	}); err != nil {
+		s.log.Error().Err(err).Msgf("error sending %s email to user (%s)", emailType, event.UserID)
+	}
+}
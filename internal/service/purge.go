@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/Closi-App/backend/internal/domain"
+	"github.com/spf13/viper"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"time"
+)
+
+// PurgeService permanently removes soft deleted content once its restore window is over.
+type PurgeService interface {
+	PurgeDeleted(ctx context.Context) error
+}
+
+type purgeService struct {
+	*Service
+	questionService QuestionService
+	answerService   AnswerService
+	commentService  CommentService
+	favoriteService FavoriteService
+	followService   FollowService
+	restoreWindow   time.Duration
+}
+
+func NewPurgeService(
+	service *Service,
+	cfg *viper.Viper,
+	questionService QuestionService,
+	answerService AnswerService,
+	commentService CommentService,
+	favoriteService FavoriteService,
+	followService FollowService,
+) PurgeService {
+	return &purgeService{
+		Service:         service,
+		questionService: questionService,
+		answerService:   answerService,
+		commentService:  commentService,
+		favoriteService: favoriteService,
+		followService:   followService,
+		restoreWindow:   getDuration(cfg, "content.restore_window", domain.DefaultRestoreWindow),
+	}
+}
+
+// PurgeDeleted purges the content deleted before the restore window, failed items are logged and retried on the next run.
+func (s *purgeService) PurgeDeleted(ctx context.Context) error {
+	deletedBefore := time.Now().Add(-s.restoreWindow)
+
+	answers, err := s.answerService.GetAll(ctx, domain.AnswerGetAllFilter{
+		DeletedBefore: &deletedBefore,
+	})
+	if err != nil {
+		return err
+	}
+
+	var purgedAnswers, purgedQuestions int
+
+	for _, answer := range answers {
+		if err = s.purgeAnswer(ctx, answer.ID); err != nil {
+			s.log.Error().Err(err).Msgf("error purging answer (%s)", answer.ID)
+			continue
+		}
+		purgedAnswers++
+	}
+
+	questions, err := s.questionService.GetAll(ctx, domain.QuestionGetAllFilter{
+		DeletedBefore: &deletedBefore,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, question := range questions {
+		if err = s.purgeQuestion(ctx, question.ID); err != nil {
+			// purged by another instance
+			if errors.Is(err, domain.ErrQuestionNotFound) {
+				continue
+			}
+
+			s.log.Error().Err(err).Msgf("error purging question (%s)", question.ID)
+			continue
+		}
+		purgedQuestions++
+	}
+
+	if purgedAnswers > 0 || purgedQuestions > 0 {
+		s.log.Info().Msgf("purged %d questions and %d answers", purgedQuestions, purgedAnswers)
+	}
+
+	return nil
+}
+
+func (s *purgeService) purgeQuestion(ctx context.Context, id bson.ObjectID) error {
+	answers, err := s.answerService.GetAll(ctx, domain.AnswerGetAllFilter{
+		QuestionID:     &id,
+		IncludeDeleted: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, answer := range answers {
+		if err = s.purgeAnswer(ctx, answer.ID); err != nil {
+			return err
+		}
+	}
+
+	if err = s.commentService.DeleteAllByTarget(ctx, domain.QuestionTarget, id); err != nil {
+		return err
+	}
+
+	if err = s.favoriteService.DeleteAllByQuestionID(ctx, id); err != nil {
+		return err
+	}
+
+	if err = s.followService.DeleteAllByTarget(ctx, domain.QuestionFollow, id); err != nil {
+		return err
+	}
+
+	return s.questionService.Purge(ctx, id)
+}
+
+func (s *purgeService) purgeAnswer(ctx context.Context, id bson.ObjectID) error {
+	if err := s.commentService.DeleteAllByTarget(ctx, domain.AnswerTarget, id); err != nil {
+		return err
+	}
+
+	return s.answerService.Purge(ctx, id)
+}
@@ -2,9 +2,12 @@ package service
 
 import (
 	"context"
+	"errors"
 	"github.com/Closi-App/backend/internal/domain"
 	"github.com/Closi-App/backend/internal/repository"
+	"github.com/spf13/viper"
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"strings"
 	"time"
 )
 
@@ -12,21 +15,65 @@ type QuestionService interface {
 	Create(ctx context.Context, input QuestionCreateInput) (bson.ObjectID, error)
 	GetAll(ctx context.Context, filter ...domain.QuestionGetAllFilter) ([]domain.Question, error)
 	GetByID(ctx context.Context, id bson.ObjectID) (domain.Question, error)
+	GetSimilar(ctx context.Context, input QuestionSimilarInput) ([]domain.Question, error)
+	GetRelated(ctx context.Context, id bson.ObjectID) ([]domain.Question, error)
 	Update(ctx context.Context, id, userID bson.ObjectID, input domain.QuestionUpdateInput) error
 	Delete(ctx context.Context, id, userID bson.ObjectID) error
+
+	Vote(ctx context.Context, id, userID bson.ObjectID, value domain.VoteValue) error
+	Unvote(ctx context.Context, id, userID bson.ObjectID) error
+
+	GetRevisions(ctx context.Context, id bson.ObjectID) ([]RevisionDetails, error)
+	Rollback(ctx context.Context, id, revisionID, userID bson.ObjectID) error
+
+	RecordView(ctx context.Context, id bson.ObjectID, viewer string) error
+	FlushViews(ctx context.Context) error
+
+	MarkDuplicate(ctx context.Context, id, originalID, userID bson.ObjectID) error
+	UnmarkDuplicate(ctx context.Context, id, userID bson.ObjectID) error
+
+	RaiseBounty(ctx context.Context, id, userID bson.ObjectID, points uint) error
+	ReleaseBounty(ctx context.Context, id bson.ObjectID) (bool, error)
+
+	SetAcceptedAnswer(ctx context.Context, id, answerID bson.ObjectID) error
+	Restore(ctx context.Context, id, userID bson.ObjectID) error
+	Purge(ctx context.Context, id bson.ObjectID) error
 }
 
 type questionService struct {
 	*Service
-	repository repository.QuestionRepository
-	tagService TagService
+	repository      repository.QuestionRepository
+	voteRepository  repository.QuestionVoteRepository
+	viewRepository  repository.QuestionViewRepository
+	tagService      TagService
+	userService     UserService
+	revisionService RevisionService
+	restoreWindow   time.Duration
+	viewDedupTTL    time.Duration
+	bountyDuration  time.Duration
 }
 
-func NewQuestionService(service *Service, repository repository.QuestionRepository, tagService TagService) QuestionService {
+func NewQuestionService(
+	service *Service,
+	cfg *viper.Viper,
+	repository repository.QuestionRepository,
+	voteRepository repository.QuestionVoteRepository,
+	viewRepository repository.QuestionViewRepository,
+	tagService TagService,
+	userService UserService,
+	revisionService RevisionService,
+) QuestionService {
 	return &questionService{
-		Service:    service,
-		repository: repository,
-		tagService: tagService,
+		Service:         service,
+		repository:      repository,
+		voteRepository:  voteRepository,
+		viewRepository:  viewRepository,
+		tagService:      tagService,
+		userService:     userService,
+		revisionService: revisionService,
+		restoreWindow:   getDuration(cfg, "content.restore_window", domain.DefaultRestoreWindow),
+		viewDedupTTL:    getDuration(cfg, "views.dedup_ttl", domain.DefaultViewDedupTTL),
+		bountyDuration:  getDuration(cfg, "bounty.duration", domain.DefaultBountyDuration),
 	}
 }
 
@@ -41,37 +88,42 @@ type QuestionCreateInput struct {
 }
 
 func (s *questionService) Create(ctx context.Context, input QuestionCreateInput) (bson.ObjectID, error) {
-	var tags []bson.ObjectID
-
-	for _, tagName := range input.Tags {
-		id, err := s.tagService.Create(ctx, TagCreateInput{
-			Name:      tagName,
-			CountryID: input.CountryID,
-		})
-		if err != nil {
-			return bson.ObjectID{}, err
-		}
+	tags, err := s.tagService.GetOrCreateAll(ctx, input.Tags, input.CountryID)
+	if err != nil {
+		return bson.ObjectID{}, err
+	}
 
-		tags = append(tags, id)
+	// points are held by the question until an answer is verified
+	if err = s.debitPoints(ctx, input.UserID, input.Points); err != nil {
+		return bson.ObjectID{}, err
 	}
 
 	id := bson.NewObjectID()
 
+	var bountyExpiresAt *time.Time
+	if input.Points > 0 {
+		bountyExpiresAt = s.bountyExpiresAt()
+	}
+
 	if err := s.repository.Create(ctx, domain.Question{
-		ID:             id,
-		Title:          input.Title,
-		Description:    input.Description,
-		AttachmentsURL: input.AttachmentsURL,
-		Tags:           tags,
-		Points:         input.Points,
-		CountryID:      input.CountryID,
-		UserID:         input.UserID,
-		CreatedAt:      time.Now(),
-		UpdatedAt:      time.Now(),
+		ID:              id,
+		Title:           input.Title,
+		Description:     input.Description,
+		AttachmentsURL:  input.AttachmentsURL,
+		Tags:            tags,
+		Points:          input.Points,
+		CountryID:       input.CountryID,
+		UserID:          input.UserID,
+		BountyExpiresAt: bountyExpiresAt,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
 	}); err != nil {
+		s.refundPoints(ctx, input.UserID, input.Points)
 		return bson.ObjectID{}, err
 	}
 
+	s.tagService.UpdateUsage(ctx, nil, tags)
+
 	return id, nil
 }
 
@@ -83,10 +135,469 @@ func (s *questionService) GetByID(ctx context.Context, id bson.ObjectID) (domain
 	return s.repository.GetByID(ctx, id)
 }
 
+type QuestionSimilarInput struct {
+	Title       string
+	Description string
+	Tags        []string
+	CountryID   bson.ObjectID
+}
+
+func (s *questionService) GetSimilar(ctx context.Context, input QuestionSimilarInput) ([]domain.Question, error) {
+	tags, err := s.tagService.GetIDsByNames(ctx, input.Tags, input.CountryID)
+	if err != nil {
+		return nil, err
+	}
+
+	text := strings.TrimSpace(input.Title + " " + input.Description)
+	if text == "" && len(tags) == 0 {
+		return []domain.Question{}, nil
+	}
+
+	return s.repository.GetSimilar(ctx, domain.QuestionSimilarFilter{
+		Text:      text,
+		Tags:      tags,
+		CountryID: input.CountryID,
+		Limit:     domain.SimilarQuestionsLimit,
+	})
+}
+
+func (s *questionService) GetRelated(ctx context.Context, id bson.ObjectID) ([]domain.Question, error) {
+	question, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetSimilar(ctx, domain.QuestionSimilarFilter{
+		Text:      strings.TrimSpace(question.Title + " " + question.Description),
+		Tags:      question.Tags,
+		CountryID: question.CountryID,
+		ExcludeID: &question.ID,
+		Limit:     domain.SimilarQuestionsLimit,
+	})
+}
+
 func (s *questionService) Update(ctx context.Context, id, userID bson.ObjectID, input domain.QuestionUpdateInput) error {
-	return s.repository.Update(ctx, id, userID, input)
+	question, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err = s.userService.CheckOwnership(ctx, userID, question.UserID); err != nil {
+		return err
+	}
+
+	var escrowDelta int
+
+	if input.Points != nil {
+		// points are escrowed from the author's balance, so only the author can change them,
+		// and not after they are paid out or refunded
+		if question.UserID != userID || question.AcceptedAnswerID != nil || question.BountyReleasedAt != nil {
+			input.Points = nil
+		} else {
+			escrowDelta = int(*input.Points) - int(question.Points)
+			if escrowDelta > 0 {
+				if err = s.debitPoints(ctx, userID, uint(escrowDelta)); err != nil {
+					return err
+				}
+			}
+
+			if question.BountyExpiresAt == nil && *input.Points > 0 {
+				input.BountyExpiresAt = s.bountyExpiresAt()
+			}
+		}
+	}
+
+	if err = s.repository.Update(ctx, id, input); err != nil {
+		if escrowDelta > 0 {
+			s.refundPoints(ctx, userID, uint(escrowDelta))
+		}
+		return err
+	}
+
+	if escrowDelta < 0 {
+		if err = s.userService.AdjustPoints(ctx, userID, -1*escrowDelta); err != nil {
+			return err
+		}
+	}
+
+	if input.Tags != nil {
+		s.tagService.UpdateUsage(ctx, question.Tags, input.Tags)
+	}
+
+	return s.recordRevision(ctx, question, userID, input, nil)
 }
 
 func (s *questionService) Delete(ctx context.Context, id, userID bson.ObjectID) error {
-	return s.repository.Delete(ctx, id, userID)
+	question, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err = s.userService.CheckOwnership(ctx, userID, question.UserID); err != nil {
+		return err
+	}
+
+	if err = s.repository.Delete(ctx, id, userID); err != nil {
+		return err
+	}
+
+	s.tagService.UpdateUsage(ctx, question.Tags, nil)
+
+	return nil
+}
+
+func (s *questionService) Vote(ctx context.Context, id, userID bson.ObjectID, value domain.VoteValue) error {
+	question, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if question.UserID == userID {
+		return domain.ErrVoteOwnQuestion
+	}
+
+	previous, err := s.voteRepository.Set(ctx, domain.QuestionVote{
+		ID:         bson.NewObjectID(),
+		QuestionID: id,
+		UserID:     userID,
+		Value:      value,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if previous.Value == value {
+		return nil
+	}
+
+	if err = s.applyVote(ctx, question, previous.Value, value); err != nil {
+		return err
+	}
+
+	reputation := previous.AppliedReputation() + s.adjustReputation(ctx, question, value.Reputation()-previous.AppliedReputation())
+
+	return s.voteRepository.SetReputation(ctx, id, userID, reputation)
+}
+
+func (s *questionService) Unvote(ctx context.Context, id, userID bson.ObjectID) error {
+	question, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	previous, err := s.voteRepository.Delete(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if err = s.applyVote(ctx, question, previous.Value, 0); err != nil {
+		return err
+	}
+
+	s.adjustReputation(ctx, question, -previous.AppliedReputation())
+
+	return nil
+}
+
+// applyVote updates question counters when the vote changes from previous to current.
+func (s *questionService) applyVote(ctx context.Context, question domain.Question, previous, current domain.VoteValue) error {
+	if previous == current {
+		return nil
+	}
+
+	var upVotes, downVotes int
+
+	for value, delta := range map[domain.VoteValue]int{previous: -1, current: 1} {
+		switch value {
+		case domain.UpVote:
+			upVotes += delta
+		case domain.DownVote:
+			downVotes += delta
+		}
+	}
+
+	return s.repository.AdjustVotes(ctx, question.ID, upVotes, downVotes)
+}
+
+// adjustReputation changes the question author reputation and returns the amount actually applied,
+// it doesn't take the author's balance below zero and deleted authors don't get it at all.
+func (s *questionService) adjustReputation(ctx context.Context, question domain.Question, points int) int {
+	applied, err := s.userService.AdjustReputation(ctx, question.UserID, points)
+	if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+		s.log.Error().Err(err).Msgf("error adjusting reputation for question author (%s)", question.UserID)
+	}
+
+	return applied
+}
+
+func (s *questionService) GetRevisions(ctx context.Context, id bson.ObjectID) ([]RevisionDetails, error) {
+	if _, err := s.repository.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.revisionService.GetAll(ctx, domain.QuestionTarget, id)
+}
+
+func (s *questionService) Rollback(ctx context.Context, id, revisionID, userID bson.ObjectID) error {
+	question, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	revision, err := s.revisionService.GetByID(ctx, domain.QuestionTarget, id, revisionID)
+	if err != nil {
+		return err
+	}
+
+	input := revision.Content.QuestionUpdateInput()
+
+	if err = s.repository.Update(ctx, id, input); err != nil {
+		return err
+	}
+
+	s.tagService.UpdateUsage(ctx, question.Tags, input.Tags)
+
+	return s.recordRevision(ctx, question, userID, input, &revision.ID)
+}
+
+// RecordView counts the question view, repeated views of the same viewer are deduplicated.
+func (s *questionService) RecordView(ctx context.Context, id bson.ObjectID, viewer string) error {
+	return s.viewRepository.Record(ctx, id, viewer, s.viewDedupTTL)
+}
+
+// FlushViews moves the views recorded in cache to questions.
+func (s *questionService) FlushViews(ctx context.Context) error {
+	// another instance is flushing
+	token, locked, err := s.viewRepository.LockFlush(ctx, domain.QuestionViewsFlushLockTTL)
+	if err != nil || !locked {
+		return err
+	}
+
+	defer func() {
+		if err := s.viewRepository.UnlockFlush(ctx, token); err != nil {
+			s.log.Error().Err(err).Msg("error unlocking views flush")
+		}
+	}()
+
+	views, err := s.viewRepository.GetPending(ctx)
+	if err != nil {
+		return err
+	}
+
+	// views that failed to flush stay in cache and are retried on the next flush
+	for _, v := range views {
+		if err = s.repository.AddViews(ctx, v); err != nil {
+			s.log.Error().Err(err).Msgf("error flushing %d views of question (%s)", v.Views, v.QuestionID)
+			continue
+		}
+
+		if err = s.viewRepository.RemoveFlushed(ctx, v); err != nil {
+			s.log.Error().Err(err).Msgf("error removing %d flushed views of question (%s)", v.Views, v.QuestionID)
+		}
+	}
+
+	return nil
+}
+
+func (s *questionService) MarkDuplicate(ctx context.Context, id, originalID, userID bson.ObjectID) error {
+	question, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err = s.userService.CheckOwnership(ctx, userID, question.UserID); err != nil {
+		return err
+	}
+
+	// link straight to the canonical question instead of building chains of duplicates,
+	// older chains are followed until a question that isn't a duplicate is reached
+	visited := map[bson.ObjectID]bool{}
+	for {
+		if originalID == id || visited[originalID] {
+			return domain.ErrQuestionInvalidOriginal
+		}
+		visited[originalID] = true
+
+		original, err := s.repository.GetByID(ctx, originalID)
+		if err != nil {
+			return err
+		}
+
+		if original.DuplicateOf == nil {
+			break
+		}
+		originalID = *original.DuplicateOf
+	}
+
+	return s.repository.SetDuplicateOf(ctx, id, &originalID)
+}
+
+func (s *questionService) UnmarkDuplicate(ctx context.Context, id, userID bson.ObjectID) error {
+	question, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err = s.userService.CheckOwnership(ctx, userID, question.UserID); err != nil {
+		return err
+	}
+
+	return s.repository.SetDuplicateOf(ctx, id, nil)
+}
+
+func (s *questionService) SetAcceptedAnswer(ctx context.Context, id, answerID bson.ObjectID) error {
+	return s.repository.SetAcceptedAnswer(ctx, id, answerID)
+}
+
+func (s *questionService) Restore(ctx context.Context, id, userID bson.ObjectID) error {
+	question, err := s.repository.GetDeletedByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// owners can restore only their own deletions, moderators can restore anything
+	if question.UserID != userID || *question.DeletedBy != userID {
+		user, err := s.userService.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		if !user.IsModerator() {
+			return domain.ErrForbidden
+		}
+	}
+
+	if time.Since(*question.DeletedAt) > s.restoreWindow {
+		return domain.ErrRestoreWindowExpired
+	}
+
+	if err = s.repository.Restore(ctx, id); err != nil {
+		return err
+	}
+
+	s.tagService.UpdateUsage(ctx, nil, question.Tags)
+
+	return nil
+}
+
+func (s *questionService) Purge(ctx context.Context, id bson.ObjectID) error {
+	question, err := s.repository.GetDeletedByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err = s.voteRepository.DeleteAllByQuestionID(ctx, id); err != nil {
+		return err
+	}
+
+	if err = s.revisionService.DeleteAllByTarget(ctx, domain.QuestionTarget, id); err != nil {
+		return err
+	}
+
+	// the question may be purged by another instance meanwhile, only the call which removed it refunds the escrow
+	if err = s.repository.Purge(ctx, id); err != nil {
+		return err
+	}
+
+	if question.HasEscrow() {
+		if err = s.userService.AdjustPoints(ctx, question.UserID, int(question.Points)); err != nil {
+			s.log.Error().Err(err).Msgf("error refunding escrowed points for question (%s)", id)
+		}
+	}
+
+	return nil
+}
+
+func (s *questionService) RaiseBounty(ctx context.Context, id, userID bson.ObjectID, points uint) error {
+	question, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// the bounty is paid from the author's balance, so moderators can't raise it
+	if question.UserID != userID {
+		return domain.ErrForbidden
+	}
+
+	if question.AcceptedAnswerID != nil || question.BountyReleasedAt != nil {
+		return domain.ErrQuestionBountyClosed
+	}
+
+	if err = s.debitPoints(ctx, userID, points); err != nil {
+		return err
+	}
+
+	// raising the bounty gives it a new deadline
+	if err = s.repository.RaiseBounty(ctx, id, points, *s.bountyExpiresAt()); err != nil {
+		s.refundPoints(ctx, userID, points)
+		return err
+	}
+
+	return nil
+}
+
+func (s *questionService) ReleaseBounty(ctx context.Context, id bson.ObjectID) (bool, error) {
+	return s.repository.ReleaseBounty(ctx, id)
+}
+
+func (s *questionService) bountyExpiresAt() *time.Time {
+	expiresAt := time.Now().Add(s.bountyDuration)
+	return &expiresAt
+}
+
+// debitPoints takes the points from the user balance atomically, domain.ErrUserInsufficientPoints is returned if it's too low.
+func (s *questionService) debitPoints(ctx context.Context, userID bson.ObjectID, points uint) error {
+	if points == 0 {
+		return nil
+	}
+
+	return s.userService.AdjustPoints(ctx, userID, -1*int(points))
+}
+
+// refundPoints returns the points debited for an operation which failed afterwards.
+func (s *questionService) refundPoints(ctx context.Context, userID bson.ObjectID, points uint) {
+	if points == 0 {
+		return
+	}
+
+	if err := s.userService.AdjustPoints(ctx, userID, int(points)); err != nil {
+		s.log.Error().Err(err).Msgf("error refunding %d points to user (%s)", points, userID)
+	}
+}
+
+func (s *questionService) recordRevision(
+	ctx context.Context,
+	question domain.Question,
+	userID bson.ObjectID,
+	input domain.QuestionUpdateInput,
+	rollbackOf *bson.ObjectID,
+) error {
+	previous := domain.NewQuestionRevisionContent(question)
+	current := previous
+
+	if input.Title != nil {
+		current.Title = *input.Title
+	}
+	if input.Description != nil {
+		current.Description = *input.Description
+	}
+	if input.AttachmentsURL != nil {
+		current.AttachmentsURL = input.AttachmentsURL
+	}
+	if input.Tags != nil {
+		current.Tags = input.Tags
+	}
+
+	return s.revisionService.Record(ctx, RevisionRecordInput{
+		TargetType:        domain.QuestionTarget,
+		TargetID:          question.ID,
+		UserID:            userID,
+		Previous:          previous,
+		Current:           current,
+		RollbackOf:        rollbackOf,
+		OriginalUserID:    question.UserID,
+		OriginalCreatedAt: question.CreatedAt,
+	})
 }
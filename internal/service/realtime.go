@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"github.com/Closi-App/backend/internal/domain"
+	"github.com/Closi-App/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"sync"
+)
+
+// RealtimeService delivers events to connected clients subscribed to question, user and notification channels.
+// Events are published to all instances, every instance delivers them to its own clients.
+type RealtimeService interface {
+	Connect(userID bson.ObjectID) *RealtimeSubscriber
+	OpenStream(ctx context.Context, userID bson.ObjectID, lastEventID string) (*RealtimeSubscriber, []domain.RealtimeMessage, error)
+	Listen(ctx context.Context) error
+}
+
+type realtimeService struct {
+	*Service
+	repository  repository.RealtimeRepository
+	mu          sync.RWMutex
+	subscribers map[string]map[*RealtimeSubscriber]struct{}
+}
+
+func NewRealtimeService(service *Service, repository repository.RealtimeRepository, eventService EventService) RealtimeService {
+	s := &realtimeService{
+		Service:     service,
+		repository:  repository,
+		subscribers: make(map[string]map[*RealtimeSubscriber]struct{}),
+	}
+
+	eventService.Subscribe(s.handleEvent)
+
+	return s
+}
+
+// RealtimeSubscriber is a connected client, it must be closed when the client disconnects.
+type RealtimeSubscriber struct {
+	service  *realtimeService
+	userID   bson.ObjectID
+	messages chan domain.RealtimeMessage
+	channels map[string]struct{}
+	closed   bool
+}
+
+func (s *realtimeService) Connect(userID bson.ObjectID) *RealtimeSubscriber {
+	return &RealtimeSubscriber{
+		service:  s,
+		userID:   userID,
+		messages: make(chan domain.RealtimeMessage, domain.RealtimeBufferSize),
+		channels: make(map[string]struct{}),
+	}
+}
+
+// OpenStream connects the user to its event stream. If lastEventID is set, the kept events which came after it
+// are returned to be sent before the new ones, which may repeat some of them.
+func (s *realtimeService) OpenStream(ctx context.Context, userID bson.ObjectID, lastEventID string) (*RealtimeSubscriber, []domain.RealtimeMessage, error) {
+	if lastEventID != "" {
+		if err := domain.ValidateStreamEventID(lastEventID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	subscriber := s.Connect(userID)
+
+	// subscribe before reading the kept events, so none is lost in between
+	s.mu.Lock()
+	subscriber.subscribe(domain.StreamChannelOf(userID))
+	s.mu.Unlock()
+
+	if lastEventID == "" {
+		return subscriber, nil, nil
+	}
+
+	messages, err := s.repository.GetStream(ctx, userID, lastEventID)
+	if err != nil {
+		subscriber.Close()
+		return nil, nil, err
+	}
+
+	return subscriber, messages, nil
+}
+
+// Listen delivers messages published by all instances to the local subscribers until the context is done.
+func (s *realtimeService) Listen(ctx context.Context) error {
+	return s.repository.Listen(ctx, s.deliver)
+}
+
+func (s *realtimeService) deliver(message domain.RealtimeMessage) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for subscriber := range s.subscribers[message.Channel] {
+		select {
+		case subscriber.messages <- message:
+		default:
+			s.log.Warn().Msgf("dropping realtime message for slow subscriber (%s)", subscriber.userID)
+		}
+	}
+}
+
+// handleEvent publishes the event to the channels of the question and the user concerned by it.
+func (s *realtimeService) handleEvent(ctx context.Context, event domain.Event) error {
+	var channels []string
+
+	switch event.Type {
+	case domain.NotificationCreatedEvent:
+		channels = append(channels, domain.NotificationsChannelOf(event.UserID))
+	default:
+		channels = append(channels, domain.UserChannel(event.UserID))
+		if event.QuestionID != nil {
+			channels = append(channels, domain.QuestionChannel(*event.QuestionID))
+		}
+	}
+
+	for _, channel := range channels {
+		if err := s.repository.Publish(ctx, domain.RealtimeMessage{
+			Channel: channel,
+			Event:   event,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if !domain.IsStreamedEvent(event.Type) {
+		return nil
+	}
+
+	id, err := s.repository.AppendStream(ctx, event.UserID, event)
+	if err != nil {
+		return err
+	}
+
+	return s.repository.Publish(ctx, domain.RealtimeMessage{
+		ID:      id,
+		Channel: domain.StreamChannelOf(event.UserID),
+		Event:   event,
+	})
+}
+
+// Messages returns the channel of messages for the subscriber, it's closed when the subscriber is closed.
+func (r *RealtimeSubscriber) Messages() <-chan domain.RealtimeMessage {
+	return r.messages
+}
+
+func (r *RealtimeSubscriber) Subscribe(channel string) error {
+	channel, err := domain.ResolveRealtimeChannel(channel, r.userID)
+	if err != nil {
+		return err
+	}
+
+	r.service.mu.Lock()
+	defer r.service.mu.Unlock()
+
+	r.subscribe(channel)
+
+	return nil
+}
+
+func (r *RealtimeSubscriber) Unsubscribe(channel string) error {
+	channel, err := domain.ResolveRealtimeChannel(channel, r.userID)
+	if err != nil {
+		return err
+	}
+
+	r.service.mu.Lock()
+	defer r.service.mu.Unlock()
+
+	r.unsubscribe(channel)
+
+	return nil
+}
+
+func (r *RealtimeSubscriber) Close() {
+	r.service.mu.Lock()
+	defer r.service.mu.Unlock()
+
+	if r.closed {
+		return
+	}
+
+	for channel := range r.channels {
+		r.unsubscribe(channel)
+	}
+
+	r.closed = true
+	close(r.messages)
+}
+
+// subscribe must be called with the service lock held.
+func (r *RealtimeSubscriber) subscribe(channel string) {
+	if r.closed {
+		return
+	}
+
+	if r.service.subscribers[channel] == nil {
+		r.service.subscribers[channel] = make(map[*RealtimeSubscriber]struct{})
+	}
+	r.service.subscribers[channel][r] = struct{}{}
+	r.channels[channel] = struct{}{}
+}
+
+// unsubscribe must be called with the service lock held.
+func (r *RealtimeSubscriber) unsubscribe(channel string) {
+	delete(r.channels, channel)
+	delete(r.service.subscribers[channel], r)
+
+	if len(r.service.subscribers[channel]) == 0 {
+		delete(r.service.subscribers, channel)
+	}
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"github.com/Closi-App/backend/internal/domain"
+	"github.com/Closi-App/backend/internal/repository"
+	"github.com/Closi-App/backend/internal/utils"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"time"
+)
+
+type RevisionService interface {
+	Record(ctx context.Context, input RevisionRecordInput) error
+	GetAll(ctx context.Context, targetType domain.TargetType, targetID bson.ObjectID) ([]RevisionDetails, error)
+	GetByID(ctx context.Context, targetType domain.TargetType, targetID, id bson.ObjectID) (domain.Revision, error)
+	DeleteAllByTarget(ctx context.Context, targetType domain.TargetType, targetID bson.ObjectID) error
+}
+
+type revisionService struct {
+	*Service
+	repository repository.RevisionRepository
+}
+
+func NewRevisionService(service *Service, repository repository.RevisionRepository) RevisionService {
+	return &revisionService{
+		Service:    service,
+		repository: repository,
+	}
+}
+
+type RevisionRecordInput struct {
+	TargetType domain.TargetType
+	TargetID   bson.ObjectID
+	UserID     bson.ObjectID
+	Previous   domain.RevisionContent
+	Current    domain.RevisionContent
+	RollbackOf *bson.ObjectID
+
+	// OriginalUserID and OriginalCreatedAt describe the content before the first recorded edit.
+	OriginalUserID    bson.ObjectID
+	OriginalCreatedAt time.Time
+}
+
+func (s *revisionService) Record(ctx context.Context, input RevisionRecordInput) error {
+	changedFields := input.Current.ChangedFields(input.Previous)
+	if len(changedFields) == 0 {
+		return nil
+	}
+
+	number, err := s.repository.NextNumber(ctx, input.TargetType, input.TargetID)
+	if err != nil {
+		return err
+	}
+
+	// the first edit records the original content as the initial revision
+	if number == 1 {
+		if err = s.repository.Create(ctx, domain.Revision{
+			ID:            bson.NewObjectID(),
+			TargetType:    input.TargetType,
+			TargetID:      input.TargetID,
+			Number:        1,
+			UserID:        input.OriginalUserID,
+			ChangedFields: nil,
+			Content:       input.Previous,
+			CreatedAt:     input.OriginalCreatedAt,
+		}); err != nil {
+			return err
+		}
+
+		if number, err = s.repository.NextNumber(ctx, input.TargetType, input.TargetID); err != nil {
+			return err
+		}
+	}
+
+	return s.repository.Create(ctx, domain.Revision{
+		ID:            bson.NewObjectID(),
+		TargetType:    input.TargetType,
+		TargetID:      input.TargetID,
+		Number:        number,
+		UserID:        input.UserID,
+		ChangedFields: changedFields,
+		Content:       input.Current,
+		RollbackOf:    input.RollbackOf,
+		CreatedAt:     time.Now(),
+	})
+}
+
+type RevisionDetails struct {
+	domain.Revision
+	Diffs map[string][]utils.DiffLine `json:"diffs"`
+}
+
+func (s *revisionService) GetAll(ctx context.Context, targetType domain.TargetType, targetID bson.ObjectID) ([]RevisionDetails, error) {
+	revisions, err := s.repository.GetAll(ctx, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	details := make([]RevisionDetails, 0, len(revisions))
+
+	for i, revision := range revisions {
+		var previous domain.RevisionContent
+		if i > 0 {
+			previous = revisions[i-1].Content
+		}
+
+		diffs := make(map[string][]utils.DiffLine, len(revision.ChangedFields))
+		for _, field := range revision.ChangedFields {
+			diffs[field] = utils.DiffLines(previous.Field(field), revision.Content.Field(field))
+		}
+
+		details = append(details, RevisionDetails{
+			Revision: revision,
+			Diffs:    diffs,
+		})
+	}
+
+	return details, nil
+}
+
+func (s *revisionService) GetByID(ctx context.Context, targetType domain.TargetType, targetID, id bson.ObjectID) (domain.Revision, error) {
+	revision, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return domain.Revision{}, err
+	}
+
+	if revision.TargetType != targetType || revision.TargetID != targetID {
+		return domain.Revision{}, domain.ErrRevisionNotFound
+	}
+
+	return revision, nil
+}
+
+func (s *revisionService) DeleteAllByTarget(ctx context.Context, targetType domain.TargetType, targetID bson.ObjectID) error {
+	return s.repository.DeleteAllByTarget(ctx, targetType, targetID)
+}
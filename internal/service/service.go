@@ -1,6 +1,10 @@
 package service
 
-import "github.com/Closi-App/backend/pkg/logger"
+import (
+	"github.com/Closi-App/backend/pkg/logger"
+	"github.com/spf13/viper"
+	"time"
+)
 
 type Service struct {
 	log *logger.Logger
@@ -11,3 +15,12 @@ func NewService(log *logger.Logger) *Service {
 		log: log,
 	}
 }
+
+// getDuration returns the duration from the config, defaultValue is returned if it isn't set or isn't positive.
+func getDuration(cfg *viper.Viper, key string, defaultValue time.Duration) time.Duration {
+	if duration := cfg.GetDuration(key); duration > 0 {
+		return duration
+	}
+
+	return defaultValue
+}
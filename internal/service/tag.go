@@ -2,28 +2,59 @@ package service
 
 import (
 	"context"
+	"errors"
 	"github.com/Closi-App/backend/internal/domain"
 	"github.com/Closi-App/backend/internal/repository"
+	"github.com/Closi-App/backend/internal/utils"
+	"github.com/spf13/viper"
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"slices"
+	"time"
 )
 
 type TagService interface {
-	Create(ctx context.Context, input TagCreateInput) (bson.ObjectID, error)
+	GetOrCreate(ctx context.Context, input TagCreateInput) (bson.ObjectID, error)
+	GetOrCreateAll(ctx context.Context, names []string, countryID bson.ObjectID) ([]bson.ObjectID, error)
+	GetIDsByNames(ctx context.Context, names []string, countryID bson.ObjectID) ([]bson.ObjectID, error)
 	GetByID(ctx context.Context, id bson.ObjectID) (domain.Tag, error)
 	GetAll(ctx context.Context) ([]domain.Tag, error)
 	GetAllByCountryID(ctx context.Context, countryID bson.ObjectID) ([]domain.Tag, error)
+	Update(ctx context.Context, id bson.ObjectID, input domain.TagUpdateInput) error
 	Delete(ctx context.Context, id bson.ObjectID) error
+
+	AddSynonym(ctx context.Context, id bson.ObjectID, name string) error
+	RemoveSynonym(ctx context.Context, id bson.ObjectID, name string) error
+	Merge(ctx context.Context, id, targetID bson.ObjectID) error
+
+	Autocomplete(ctx context.Context, countryID bson.ObjectID, prefix string, limit int) ([]domain.Tag, error)
+	GetTrending(ctx context.Context, countryID bson.ObjectID, limit int) ([]domain.TrendingTag, error)
+	UpdateUsage(ctx context.Context, previous, current []bson.ObjectID)
 }
 
 type tagService struct {
 	*Service
-	repository repository.TagRepository
+	repository         repository.TagRepository
+	questionRepository repository.QuestionRepository
+	revisionRepository repository.RevisionRepository
+	followRepository   repository.FollowRepository
+	trendingWindow     time.Duration
 }
 
-func NewTagService(service *Service, repository repository.TagRepository) TagService {
+func NewTagService(
+	service *Service,
+	cfg *viper.Viper,
+	repository repository.TagRepository,
+	questionRepository repository.QuestionRepository,
+	revisionRepository repository.RevisionRepository,
+	followRepository repository.FollowRepository,
+) TagService {
 	return &tagService{
-		Service:    service,
-		repository: repository,
+		Service:            service,
+		repository:         repository,
+		questionRepository: questionRepository,
+		revisionRepository: revisionRepository,
+		followRepository:   followRepository,
+		trendingWindow:     getDuration(cfg, "tags.trending_window", domain.DefaultTagTrendingWindow),
 	}
 }
 
@@ -32,18 +63,70 @@ type TagCreateInput struct {
 	CountryID bson.ObjectID
 }
 
-func (s *tagService) Create(ctx context.Context, input TagCreateInput) (bson.ObjectID, error) {
-	id := bson.NewObjectID()
+func (s *tagService) GetOrCreate(ctx context.Context, input TagCreateInput) (bson.ObjectID, error) {
+	name := domain.NormalizeTagName(input.Name)
+	if name == "" {
+		return bson.ObjectID{}, domain.ErrTagInvalidName
+	}
 
-	if err := s.repository.Create(ctx, domain.Tag{
-		ID:        id,
-		Name:      input.Name,
+	tag, err := s.repository.GetOrCreate(ctx, domain.Tag{
+		ID:        bson.NewObjectID(),
+		Name:      name,
 		CountryID: input.CountryID,
-	}); err != nil {
+	})
+	if err != nil {
 		return bson.ObjectID{}, err
 	}
 
-	return id, nil
+	return tag.ID, nil
+}
+
+// GetOrCreateAll resolves tag names to IDs, skipping names which normalize to the same tag.
+func (s *tagService) GetOrCreateAll(ctx context.Context, names []string, countryID bson.ObjectID) ([]bson.ObjectID, error) {
+	var ids []bson.ObjectID
+
+	seen := make(map[bson.ObjectID]struct{}, len(names))
+
+	for _, name := range names {
+		id, err := s.GetOrCreate(ctx, TagCreateInput{
+			Name:      name,
+			CountryID: countryID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := seen[id]; ok {
+			continue
+		}
+
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// GetIDsByNames resolves names and synonyms of existing tags to IDs, unknown names are skipped.
+func (s *tagService) GetIDsByNames(ctx context.Context, names []string, countryID bson.ObjectID) ([]bson.ObjectID, error) {
+	var ids []bson.ObjectID
+
+	for _, name := range names {
+		tag, err := s.repository.GetByName(ctx, countryID, domain.NormalizeTagName(name))
+		if err != nil {
+			if errors.Is(err, domain.ErrTagNotFound) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		if !slices.Contains(ids, tag.ID) {
+			ids = append(ids, tag.ID)
+		}
+	}
+
+	return ids, nil
 }
 
 func (s *tagService) GetByID(ctx context.Context, id bson.ObjectID) (domain.Tag, error) {
@@ -58,6 +141,173 @@ func (s *tagService) GetAllByCountryID(ctx context.Context, countryID bson.Objec
 	return s.repository.GetAllByCountryID(ctx, countryID)
 }
 
+func (s *tagService) Update(ctx context.Context, id bson.ObjectID, input domain.TagUpdateInput) error {
+	tag, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if input.Name != nil {
+		name := domain.NormalizeTagName(*input.Name)
+		if name == "" {
+			return domain.ErrTagInvalidName
+		}
+
+		if err = s.checkNameIsFree(ctx, tag, name); err != nil {
+			return err
+		}
+
+		input.Name = &name
+	}
+
+	for lang := range input.Descriptions {
+		if _, err = utils.ParseLanguage(lang); err != nil {
+			return domain.ErrBadRequest
+		}
+	}
+
+	return s.repository.Update(ctx, id, input)
+}
+
 func (s *tagService) Delete(ctx context.Context, id bson.ObjectID) error {
-	return s.repository.Delete(ctx, id)
+	if err := s.repository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	return s.followRepository.DeleteAllByTarget(ctx, domain.TagFollow, id)
+}
+
+func (s *tagService) Autocomplete(ctx context.Context, countryID bson.ObjectID, prefix string, limit int) ([]domain.Tag, error) {
+	return s.repository.Search(ctx, countryID, domain.NormalizeTagName(prefix), domain.ClampTagLimit(limit))
+}
+
+func (s *tagService) GetTrending(ctx context.Context, countryID bson.ObjectID, limit int) ([]domain.TrendingTag, error) {
+	return s.repository.GetTrending(ctx, domain.TagTrendingFilter{
+		CountryID: countryID,
+		Since:     time.Now().Add(-s.trendingWindow),
+		Limit:     domain.ClampTagLimit(limit),
+	})
+}
+
+// UpdateUsage adjusts usage counters of the tags which were added to or removed from a question.
+// Counters are informational, so errors are only logged.
+func (s *tagService) UpdateUsage(ctx context.Context, previous, current []bson.ObjectID) {
+	var added, removed []bson.ObjectID
+
+	for _, id := range current {
+		if !slices.Contains(previous, id) {
+			added = append(added, id)
+		}
+	}
+	for _, id := range previous {
+		if !slices.Contains(current, id) {
+			removed = append(removed, id)
+		}
+	}
+
+	if err := s.repository.AdjustUsage(ctx, added, 1); err != nil {
+		s.log.Error().Err(err).Msg("error incrementing tags usage")
+	}
+	if err := s.repository.AdjustUsage(ctx, removed, -1); err != nil {
+		s.log.Error().Err(err).Msg("error decrementing tags usage")
+	}
+}
+
+func (s *tagService) AddSynonym(ctx context.Context, id bson.ObjectID, name string) error {
+	tag, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	name = domain.NormalizeTagName(name)
+	if name == "" {
+		return domain.ErrTagInvalidName
+	}
+
+	if name == tag.Name {
+		return domain.ErrTagAlreadyExists
+	}
+
+	if err = s.checkNameIsFree(ctx, tag, name); err != nil {
+		return err
+	}
+
+	return s.repository.AddSynonyms(ctx, id, name)
+}
+
+func (s *tagService) RemoveSynonym(ctx context.Context, id bson.ObjectID, name string) error {
+	return s.repository.RemoveSynonym(ctx, id, domain.NormalizeTagName(name))
+}
+
+// Merge moves questions, their revisions and followers of the tag to the target tag and deletes the tag,
+// its name and synonyms become synonyms of the target tag.
+func (s *tagService) Merge(ctx context.Context, id, targetID bson.ObjectID) error {
+	if id == targetID {
+		return domain.ErrBadRequest
+	}
+
+	tag, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	target, err := s.repository.GetByID(ctx, targetID)
+	if err != nil {
+		return err
+	}
+
+	if tag.CountryID != target.CountryID {
+		return domain.ErrBadRequest
+	}
+
+	if err = s.questionRepository.ReplaceTag(ctx, id, targetID); err != nil {
+		return err
+	}
+
+	if err = s.revisionRepository.ReplaceTag(ctx, id, targetID); err != nil {
+		return err
+	}
+
+	if err = s.followRepository.ReplaceTarget(ctx, domain.TagFollow, id, targetID); err != nil {
+		return err
+	}
+
+	if err = s.repository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if err = s.repository.AddSynonyms(ctx, targetID, append(tag.Synonyms, tag.Name)...); err != nil {
+		return err
+	}
+
+	descriptions := make(map[string]string)
+	for lang, description := range tag.Descriptions {
+		if _, ok := target.Descriptions[lang]; !ok {
+			descriptions[lang] = description
+		}
+	}
+
+	if err = s.repository.Update(ctx, targetID, domain.TagUpdateInput{Descriptions: descriptions}); err != nil {
+		return err
+	}
+
+	return s.repository.RecountUsage(ctx, targetID)
+}
+
+// checkNameIsFree returns domain.ErrTagAlreadyExists if the name is used by another tag of the country.
+func (s *tagService) checkNameIsFree(ctx context.Context, tag domain.Tag, name string) error {
+	existing, err := s.repository.GetByName(ctx, tag.CountryID, name)
+	if err != nil {
+		if errors.Is(err, domain.ErrTagNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	if existing.ID != tag.ID {
+		return domain.ErrTagAlreadyExists
+	}
+
+	return nil
 }
@@ -20,9 +20,9 @@ type UserService interface {
 	UpdateSettings(ctx context.Context, id bson.ObjectID, input domain.UserSettingsUpdateInput) error
 	Delete(ctx context.Context, id bson.ObjectID) error
 
+	CheckOwnership(ctx context.Context, id, ownerID bson.ObjectID) error
 	AdjustPoints(ctx context.Context, id bson.ObjectID, pointsAmount int) error
-	AddFavorite(ctx context.Context, id, questionID bson.ObjectID) error
-	RemoveFavorite(ctx context.Context, id, questionID bson.ObjectID) error
+	AdjustReputation(ctx context.Context, id bson.ObjectID, pointsAmount int) (int, error)
 	AddAchievement(ctx context.Context, id, achievementID bson.ObjectID) error
 	RemoveAchievement(ctx context.Context, id, achievementID bson.ObjectID) error
 	SetSubscription(ctx context.Context, id bson.ObjectID, subscription domain.Subscription) error
@@ -36,7 +36,11 @@ type UserService interface {
 type userService struct {
 	*Service
 	repository             repository.UserRepository
+	followRepository       repository.FollowRepository
+	favoriteRepository     repository.FavoriteRepository
+	questionRepository     repository.QuestionRepository
 	emailService           EmailService
+	eventService           EventService
 	passwordHasher         auth.PasswordHasher
 	tokensManager          auth.TokensManager
 	refreshTokenTTL        time.Duration
@@ -47,14 +51,22 @@ func NewUserService(
 	service *Service,
 	cfg *viper.Viper,
 	repository repository.UserRepository,
+	followRepository repository.FollowRepository,
+	favoriteRepository repository.FavoriteRepository,
+	questionRepository repository.QuestionRepository,
 	emailService EmailService,
+	eventService EventService,
 	passwordHasher auth.PasswordHasher,
 	tokensManager auth.TokensManager,
 ) UserService {
 	return &userService{
 		Service:                service,
 		repository:             repository,
+		followRepository:       followRepository,
+		favoriteRepository:     favoriteRepository,
+		questionRepository:     questionRepository,
 		emailService:           emailService,
+		eventService:           eventService,
 		passwordHasher:         passwordHasher,
 		tokensManager:          tokensManager,
 		refreshTokenTTL:        cfg.GetDuration("auth.tokens.refresh_token.ttl"),
@@ -93,16 +105,17 @@ func (s *userService) SignUp(ctx context.Context, input UserSignUpInput) (Tokens
 		Password:     hashedPassword,
 		AvatarURL:    "",
 		Points:       domain.UserDefaultPoints,
-		Favorites:    nil,
 		Achievements: nil,
 		ReferralCode: referralCode,
 		Subscription: domain.NewSubscription(domain.FreeSubscription),
 		Settings: domain.UserSettings{
-			CountryID:          input.CountryID,
-			Language:           input.Language,
-			EmailNotifications: true,
-			Appearance:         domain.LightAppearance,
+			CountryID:        input.CountryID,
+			Language:         input.Language,
+			Timezone:         domain.UserDefaultTimezone,
+			EmailPreferences: domain.DefaultEmailPreferences(),
+			Appearance:       domain.LightAppearance,
 		},
+		Role:        domain.UserRole,
 		IsConfirmed: false,
 		IsBlocked:   false,
 		CreatedAt:   time.Now(),
@@ -114,25 +127,33 @@ func (s *userService) SignUp(ctx context.Context, input UserSignUpInput) (Tokens
 	if input.ReferrerCode != "" {
 		referrer, err := s.repository.GetByReferralCode(ctx, input.ReferrerCode)
 		if err == nil {
-			if err := s.repository.AdjustPoints(ctx, referrer.ID, domain.UserReferralPoints); err != nil {
+			if err := s.AdjustPoints(ctx, referrer.ID, domain.UserReferralPoints); err != nil {
 				s.log.Error().Err(err).Msgf("error adjusting points for referrer (%s)", referrer.ID)
 			}
-			if err := s.repository.AdjustPoints(ctx, id, domain.UserReferralPoints); err != nil {
+			if err := s.AdjustPoints(ctx, id, domain.UserReferralPoints); err != nil {
 				s.log.Error().Err(err).Msgf("error adjusting points for referral (%s)", id)
 			}
+
+			s.eventService.Publish(ctx, domain.Event{
+				Type:    domain.ReferralJoinedEvent,
+				UserID:  referrer.ID,
+				ActorID: &id,
+				Points:  domain.UserReferralPoints,
+			})
 		}
 	}
 
-	if err = s.emailService.Send(input.Email, domain.WelcomeEmail, input.Language, domain.WelcomeEmailData{
+	// the user is already created, so failing to queue the emails doesn't fail the sign up
+	if err = s.emailService.Send(ctx, input.Email, domain.WelcomeEmail, input.Language, domain.WelcomeEmailData{
 		Name: input.Name,
 	}); err != nil {
-		return Tokens{}, err
+		s.log.Error().Err(err).Msgf("error queueing welcome email for user (%s)", id)
 	}
 
-	if err = s.emailService.Send(input.Email, domain.ConfirmationEmail, input.Language, domain.ConfirmationEmailData{
+	if err = s.emailService.Send(ctx, input.Email, domain.ConfirmationEmail, input.Language, domain.ConfirmationEmailData{
 		ConfirmationLink: s.generateConfirmationLink(id),
 	}); err != nil {
-		return Tokens{}, err
+		s.log.Error().Err(err).Msgf("error queueing confirmation email for user (%s)", id)
 	}
 
 	return s.createSession(ctx, id)
@@ -181,7 +202,7 @@ func (s *userService) Update(ctx context.Context, id bson.ObjectID, input domain
 		}
 
 		if user.Email != *input.Email {
-			if err = s.emailService.Send(*input.Email, domain.ConfirmationEmail, user.Settings.Language, domain.ConfirmationEmailData{
+			if err = s.emailService.Send(ctx, *input.Email, domain.ConfirmationEmail, user.Settings.Language, domain.ConfirmationEmailData{
 				ConfirmationLink: s.generateConfirmationLink(id),
 			}); err != nil {
 				return err
@@ -197,19 +218,88 @@ func (s *userService) UpdateSettings(ctx context.Context, id bson.ObjectID, inpu
 }
 
 func (s *userService) Delete(ctx context.Context, id bson.ObjectID) error {
-	return s.repository.Delete(ctx, id)
+	if err := s.repository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.followRepository.DeleteAllByUserID(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.followRepository.DeleteAllByTarget(ctx, domain.UserFollow, id); err != nil {
+		return err
+	}
+
+	questionIDs, err := s.favoriteRepository.DeleteAllByUserID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	for _, questionID := range questionIDs {
+		if err = s.questionRepository.AdjustFavorites(ctx, questionID, -1); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-func (s *userService) AdjustPoints(ctx context.Context, id bson.ObjectID, pointsAmount int) error {
-	return s.repository.AdjustPoints(ctx, id, pointsAmount)
+// CheckOwnership returns domain.ErrForbidden unless the user owns the content or is a moderator.
+func (s *userService) CheckOwnership(ctx context.Context, id, ownerID bson.ObjectID) error {
+	if id == ownerID {
+		return nil
+	}
+
+	user, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !user.IsModerator() {
+		return domain.ErrForbidden
+	}
+
+	return nil
 }
 
-func (s *userService) AddFavorite(ctx context.Context, id, questionID bson.ObjectID) error {
-	return s.repository.AddFavorite(ctx, id, questionID)
+func (s *userService) AdjustPoints(ctx context.Context, id bson.ObjectID, pointsAmount int) error {
+	balance, err := s.repository.AdjustPoints(ctx, id, pointsAmount)
+	if err != nil {
+		return err
+	}
+
+	s.eventService.Publish(ctx, domain.Event{
+		Type:    domain.PointsChangedEvent,
+		UserID:  id,
+		Points:  pointsAmount,
+		Balance: &balance,
+	})
+
+	return nil
 }
 
-func (s *userService) RemoveFavorite(ctx context.Context, id, questionID bson.ObjectID) error {
-	return s.repository.RemoveFavorite(ctx, id, questionID)
+// AdjustReputation changes the balance by the reputation points, negative points take at most the whole balance.
+// The amount actually applied is returned, so it can be reversed exactly.
+func (s *userService) AdjustReputation(ctx context.Context, id bson.ObjectID, pointsAmount int) (int, error) {
+	if pointsAmount == 0 {
+		return 0, nil
+	}
+
+	applied, balance, err := s.repository.AdjustPointsClamped(ctx, id, pointsAmount)
+	if err != nil {
+		return 0, err
+	}
+
+	if applied != 0 {
+		s.eventService.Publish(ctx, domain.Event{
+			Type:    domain.PointsChangedEvent,
+			UserID:  id,
+			Points:  applied,
+			Balance: &balance,
+		})
+	}
+
+	return applied, nil
 }
 
 func (s *userService) AddAchievement(ctx context.Context, id, achievementID bson.ObjectID) error {
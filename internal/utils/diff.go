@@ -0,0 +1,117 @@
+package utils
+
+import "strings"
+
+const (
+	DiffEqual  DiffOperation = "="
+	DiffInsert DiffOperation = "+"
+	DiffDelete DiffOperation = "-"
+)
+
+type DiffOperation string
+
+type DiffLine struct {
+	Operation DiffOperation `json:"operation"`
+	Text      string        `json:"text"`
+}
+
+// diffMaxCells limits the size of the longest common subsequence table, changed parts of the texts
+// needing a bigger table are diffed as a whole deletion followed by a whole insertion.
+const diffMaxCells = 1 << 20
+
+// DiffLines returns a line based diff between two texts built on the longest common subsequence.
+// Common leading and trailing lines are matched up front, so usual edits of long texts stay cheap.
+func DiffLines(oldText, newText string) []DiffLine {
+	oldLines, newLines := splitLines(oldText), splitLines(newText)
+
+	var prefix, suffix int
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+
+	var diff []DiffLine
+
+	for _, line := range oldLines[:prefix] {
+		diff = append(diff, DiffLine{Operation: DiffEqual, Text: line})
+	}
+
+	diff = append(diff, diffChangedLines(oldLines[prefix:len(oldLines)-suffix], newLines[prefix:len(newLines)-suffix])...)
+
+	for _, line := range oldLines[len(oldLines)-suffix:] {
+		diff = append(diff, DiffLine{Operation: DiffEqual, Text: line})
+	}
+
+	return diff
+}
+
+func diffChangedLines(oldLines, newLines []string) []DiffLine {
+	n, m := len(oldLines), len(newLines)
+
+	if (n+1)*(m+1) > diffMaxCells {
+		diff := make([]DiffLine, 0, n+m)
+		for _, line := range oldLines {
+			diff = append(diff, DiffLine{Operation: DiffDelete, Text: line})
+		}
+		for _, line := range newLines {
+			diff = append(diff, DiffLine{Operation: DiffInsert, Text: line})
+		}
+
+		return diff
+	}
+
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var (
+		diff []DiffLine
+		i, j int
+	)
+
+	for i < n && j < m {
+		switch {
+		case oldLines[i] == newLines[j]:
+			diff = append(diff, DiffLine{Operation: DiffEqual, Text: oldLines[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, DiffLine{Operation: DiffDelete, Text: oldLines[i]})
+			i++
+		default:
+			diff = append(diff, DiffLine{Operation: DiffInsert, Text: newLines[j]})
+			j++
+		}
+	}
+
+	for ; i < n; i++ {
+		diff = append(diff, DiffLine{Operation: DiffDelete, Text: oldLines[i]})
+	}
+	for ; j < m; j++ {
+		diff = append(diff, DiffLine{Operation: DiffInsert, Text: newLines[j]})
+	}
+
+	return diff
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
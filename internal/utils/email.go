@@ -7,17 +7,31 @@ import (
 )
 
 func ParseHTMLTemplates(data interface{}, templates ...string) (bytes.Buffer, error) {
+	t, err := CompileHTMLTemplates(templates...)
+	if err != nil {
+		return bytes.Buffer{}, err
+	}
+
+	return ExecuteHTMLTemplate(t, data)
+}
+
+// CompileHTMLTemplates parses the templates once, so they can be executed many times.
+func CompileHTMLTemplates(templates ...string) (*template.Template, error) {
 	if len(templates) == 0 {
-		return bytes.Buffer{}, errors.New("no templates defined")
+		return nil, errors.New("no templates defined")
 	}
 
 	t, err := template.ParseFiles(templates...)
 	if err != nil {
-		return bytes.Buffer{}, errors.Wrap(err, "error parsing html template")
+		return nil, errors.Wrap(err, "error parsing html template")
 	}
 
+	return t, nil
+}
+
+func ExecuteHTMLTemplate(t *template.Template, data interface{}) (bytes.Buffer, error) {
 	var body bytes.Buffer
-	if err = t.Execute(&body, data); err != nil {
+	if err := t.Execute(&body, data); err != nil {
 		return bytes.Buffer{}, errors.Wrap(err, "error executing html template")
 	}
 
@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"golang.org/x/net/html"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var (
+	spacesRegexp     = regexp.MustCompile(`[ \t\r\f\v]+`)
+	blankLinesRegexp = regexp.MustCompile(`\n{3,}`)
+)
+
+// HTMLToText converts an HTML document to plain text, block elements start new lines and links keep their URLs.
+func HTMLToText(document string) (string, error) {
+	tokenizer := html.NewTokenizer(strings.NewReader(document))
+
+	var (
+		text  strings.Builder
+		skip  int
+		links []link
+	)
+
+	for {
+		tokenType := tokenizer.Next()
+
+		switch tokenType {
+		case html.ErrorToken:
+			if err := tokenizer.Err(); err != io.EOF {
+				return "", err
+			}
+
+			return cleanText(text.String()), nil
+		case html.TextToken:
+			if skip == 0 {
+				text.WriteString(strings.ReplaceAll(string(tokenizer.Text()), "\n", " "))
+			}
+		case html.StartTagToken, html.SelfClosingTagToken, html.EndTagToken:
+			name, hasAttr := tokenizer.TagName()
+			tag := string(name)
+			start := tokenType != html.EndTagToken
+
+			switch tag {
+			case "head", "style", "script", "title":
+				if tokenType == html.StartTagToken {
+					skip++
+				} else if tokenType == html.EndTagToken && skip > 0 {
+					skip--
+				}
+			case "br":
+				text.WriteString("\n")
+			case "p", "div", "h1", "h2", "h3", "h4", "h5", "h6", "ul", "ol", "table", "tr", "main", "header", "footer":
+				text.WriteString("\n\n")
+			case "li":
+				if start {
+					text.WriteString("\n- ")
+				}
+			case "a":
+				if start {
+					var href string
+					for hasAttr {
+						var key, value []byte
+						key, value, hasAttr = tokenizer.TagAttr()
+						if string(key) == "href" {
+							href = string(value)
+						}
+					}
+					links = append(links, link{href: href, start: text.Len()})
+				} else if len(links) > 0 {
+					l := links[len(links)-1]
+					links = links[:len(links)-1]
+
+					// links without text, like images, are dropped
+					hasText := strings.TrimSpace(text.String()[l.start:]) != ""
+					if hasText && l.href != "" && !strings.HasPrefix(l.href, "#") {
+						text.WriteString(" (" + l.href + ")")
+					}
+				}
+			}
+		}
+	}
+}
+
+type link struct {
+	href  string
+	start int
+}
+
+func cleanText(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(spacesRegexp.ReplaceAllString(line, " "))
+	}
+
+	return strings.TrimSpace(blankLinesRegexp.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
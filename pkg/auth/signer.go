@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"strings"
+)
+
+// Signer signs values passed to users in links, so they can't be forged. Signed values don't expire.
+type Signer interface {
+	Sign(value string) string
+	Verify(token string) (value string, err error)
+}
+
+type signer struct {
+	signingKey []byte
+}
+
+func NewSigner(cfg *viper.Viper) Signer {
+	// anyone could sign values with an empty key
+	signingKey := cfg.GetString("auth.signing_key")
+	if signingKey == "" {
+		panic("auth.signing_key must be set")
+	}
+
+	return &signer{
+		signingKey: []byte(signingKey),
+	}
+}
+
+func (s *signer) Sign(value string) string {
+	encoding := base64.RawURLEncoding
+
+	return encoding.EncodeToString([]byte(value)) + "." + encoding.EncodeToString(s.mac(value))
+}
+
+func (s *signer) Verify(token string) (string, error) {
+	encoding := base64.RawURLEncoding
+
+	encodedValue, encodedMAC, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", errors.New("invalid signed token")
+	}
+
+	value, err := encoding.DecodeString(encodedValue)
+	if err != nil {
+		return "", errors.Wrap(err, "error decoding signed token")
+	}
+
+	mac, err := encoding.DecodeString(encodedMAC)
+	if err != nil {
+		return "", errors.Wrap(err, "error decoding signed token")
+	}
+
+	if !hmac.Equal(mac, s.mac(string(value))) {
+		return "", errors.New("invalid signed token signature")
+	}
+
+	return string(value), nil
+}
+
+func (s *signer) mac(value string) []byte {
+	h := hmac.New(sha256.New, s.signingKey)
+	h.Write([]byte(value))
+
+	return h.Sum(nil)
+}
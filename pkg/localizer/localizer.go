@@ -1,6 +1,7 @@
 package localizer
 
 import (
+	"fmt"
 	"github.com/bytedance/sonic"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 	"github.com/spf13/viper"
@@ -62,3 +63,27 @@ func (s *Localizer) Translate(messageID string, data ...interface{}) string {
 
 	return localizer.MustLocalize(&cfg)
 }
+
+// Localize translates the message like Translate, but returns an error instead of panicking
+// and doesn't fall back to the default language.
+func (s *Localizer) Localize(messageID string, data ...interface{}) (string, error) {
+	localizer := i18n.NewLocalizer(s.bundle, s.language.String())
+
+	cfg := i18n.LocalizeConfig{
+		MessageID: messageID,
+	}
+	if len(data) > 0 {
+		cfg.TemplateData = data[0]
+	}
+
+	message, tag, err := localizer.LocalizeWithTag(&cfg)
+	if err != nil {
+		return "", err
+	}
+
+	if tag != s.language {
+		return "", fmt.Errorf("message %q not found in %s locale", messageID, s.language)
+	}
+
+	return message, nil
+}
@@ -0,0 +1,50 @@
+package smtp
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// fileSender writes emails as .eml files to a directory instead of sending them.
+type fileSender struct {
+	dir     string
+	from    fromAddress
+	counter atomic.Uint64
+}
+
+func newFileSender(cfg *viper.Viper) Sender {
+	dir := cfg.GetString("smtp.dir")
+	if dir == "" {
+		panic("smtp.dir must be set for the file sender")
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		panic("error creating emails directory: " + err.Error())
+	}
+
+	return &fileSender{
+		dir:  dir,
+		from: newFromAddress(cfg),
+	}
+}
+
+func (s *fileSender) Send(input SendInput) error {
+	name := fmt.Sprintf("%s-%d.eml", time.Now().Format("20060102-150405.000000"), s.counter.Add(1))
+
+	file, err := os.Create(filepath.Join(s.dir, name))
+	if err != nil {
+		return errors.Wrap(err, "error creating email file")
+	}
+	defer file.Close()
+
+	if _, err = newMessage(s.from, input).WriteTo(file); err != nil {
+		return errors.Wrap(err, "error writing email file")
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package smtp
+
+import (
+	"github.com/Closi-App/backend/pkg/logger"
+)
+
+// logSender logs emails instead of sending them, the body is logged on debug level.
+type logSender struct {
+	log *logger.Logger
+}
+
+func newLogSender(log *logger.Logger) Sender {
+	return &logSender{
+		log: log,
+	}
+}
+
+func (s *logSender) Send(input SendInput) error {
+	s.log.Info().
+		Strs("to", input.To).
+		Str("subject", input.Subject).
+		Msg("email is sent")
+
+	s.log.Debug().
+		Strs("to", input.To).
+		Str("content_type", string(input.ContentType)).
+		Msg(input.Body)
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package smtp
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// MemorySenderLimit is the amount of the latest emails kept by the memory sender.
+const MemorySenderLimit = 100
+
+// Capturer is implemented by the senders which keep sent emails, so they can be inspected in development and tests.
+type Capturer interface {
+	Messages() []CapturedMessage
+	Reset()
+}
+
+type CapturedMessage struct {
+	To          []string            `json:"to"`
+	Subject     string              `json:"subject"`
+	Body        string              `json:"body"`
+	TextBody    string              `json:"text_body,omitempty"`
+	Headers     map[string][]string `json:"headers,omitempty"`
+	ContentType ContentType         `json:"content_type"`
+	SentAt      time.Time           `json:"sent_at"`
+}
+
+// MemorySender keeps emails in memory instead of sending them.
+type MemorySender struct {
+	mu       sync.Mutex
+	messages []CapturedMessage
+}
+
+func NewMemorySender() *MemorySender {
+	return &MemorySender{}
+}
+
+func (s *MemorySender) Send(input SendInput) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages = append(s.messages, CapturedMessage{
+		To:          slices.Clone(input.To),
+		Subject:     input.Subject,
+		Body:        input.Body,
+		TextBody:    input.TextBody,
+		Headers:     maps.Clone(input.Headers),
+		ContentType: input.ContentType,
+		SentAt:      time.Now(),
+	})
+
+	if len(s.messages) > MemorySenderLimit {
+		s.messages = slices.Delete(s.messages, 0, len(s.messages)-MemorySenderLimit)
+	}
+
+	return nil
+}
+
+// Messages returns the kept emails, oldest first.
+func (s *MemorySender) Messages() []CapturedMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.messages)
+}
+
+func (s *MemorySender) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages = nil
+}
@@ -0,0 +1,165 @@
+package smtp
+
+import (
+	"bufio"
+	"encoding/base64"
+	"github.com/pkg/errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"strings"
+)
+
+// Report is a recipient entry of a delivery status notification (RFC 3464) or a complaint feedback report (RFC 5965).
+type Report struct {
+	Recipient  string
+	Action     string
+	Status     string
+	Diagnostic string
+	Complaint  bool
+}
+
+// IsPermanent reports whether the address shouldn't be emailed anymore, temporary failures are retried by the outbox.
+func (r Report) IsPermanent() bool {
+	return r.Complaint || (r.Action == "failed" && strings.HasPrefix(r.Status, "5"))
+}
+
+// ParseReport parses a multipart/report message, the reports of delivered or delayed emails are returned as well.
+func ParseReport(r io.Reader) ([]Report, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading report message")
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing report content type")
+	}
+	if mediaType != "multipart/report" {
+		return nil, errors.Errorf("unexpected report content type: %s", mediaType)
+	}
+
+	var (
+		reports    []Report
+		originalTo string
+	)
+
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := parts.NextPart()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, errors.Wrap(err, "error reading report part")
+		}
+
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+
+		var body io.Reader = part
+		if strings.EqualFold(part.Header.Get("Content-Transfer-Encoding"), "base64") {
+			body = base64.NewDecoder(base64.StdEncoding, part)
+		}
+
+		switch partType {
+		case "message/delivery-status", "message/global-delivery-status":
+			statuses, err := parseDeliveryStatus(body)
+			if err != nil {
+				return nil, err
+			}
+			reports = append(reports, statuses...)
+		case "message/feedback-report":
+			report, err := parseFeedbackReport(body)
+			if err != nil {
+				return nil, err
+			}
+			reports = append(reports, report)
+		case "message/rfc822", "text/rfc822-headers":
+			original, err := mail.ReadMessage(body)
+			if err == nil {
+				originalTo = original.Header.Get("To")
+			}
+		}
+	}
+
+	// complaint reports may omit Original-Rcpt-To, the recipient is taken from the attached original email then
+	for i := range reports {
+		if reports[i].Recipient == "" && originalTo != "" {
+			if address, err := mail.ParseAddress(originalTo); err == nil {
+				reports[i].Recipient = address.Address
+			}
+		}
+	}
+
+	if len(reports) == 0 {
+		return nil, errors.New("no recipients found in report")
+	}
+
+	return reports, nil
+}
+
+// parseDeliveryStatus parses the per-message fields followed by the per-recipient fields groups.
+func parseDeliveryStatus(r io.Reader) ([]Report, error) {
+	reader := textproto.NewReader(bufio.NewReader(r))
+
+	var (
+		reports    []Report
+		perMessage = true
+	)
+
+	for {
+		fields, err := reader.ReadMIMEHeader()
+		if len(fields) > 0 {
+			if perMessage {
+				perMessage = false
+			} else {
+				reports = append(reports, Report{
+					Recipient:  parseRecipient(fields),
+					Action:     strings.ToLower(strings.TrimSpace(fields.Get("Action"))),
+					Status:     strings.TrimSpace(fields.Get("Status")),
+					Diagnostic: strings.TrimSpace(fields.Get("Diagnostic-Code")),
+				})
+			}
+		}
+
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return reports, nil
+			}
+			return nil, errors.Wrap(err, "error parsing delivery status")
+		}
+	}
+}
+
+func parseFeedbackReport(r io.Reader) (Report, error) {
+	fields, err := textproto.NewReader(bufio.NewReader(r)).ReadMIMEHeader()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return Report{}, errors.Wrap(err, "error parsing feedback report")
+	}
+
+	return Report{
+		Recipient:  stripAddress(fields.Get("Original-Rcpt-To")),
+		Diagnostic: strings.TrimSpace(fields.Get("Feedback-Type")),
+		Complaint:  true,
+	}, nil
+}
+
+// parseRecipient returns the address of the "rfc822; user@example.com" recipient field.
+func parseRecipient(fields textproto.MIMEHeader) string {
+	recipient := fields.Get("Final-Recipient")
+	if recipient == "" {
+		recipient = fields.Get("Original-Recipient")
+	}
+
+	if _, address, ok := strings.Cut(recipient, ";"); ok {
+		recipient = address
+	}
+
+	return stripAddress(recipient)
+}
+
+func stripAddress(address string) string {
+	return strings.Trim(strings.TrimSpace(address), "<>")
+}
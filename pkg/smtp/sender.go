@@ -1,13 +1,38 @@
 package smtp
 
+import (
+	"github.com/Closi-App/backend/pkg/logger"
+	"github.com/spf13/viper"
+)
+
 type Sender interface {
 	Send(input SendInput) error
 }
 
+// NewSender returns the sender chosen by smtp.sender config: "smtp" (default) sends emails through the SMTP server,
+// "file" writes them to smtp.dir, "log" logs them and "memory" keeps them in memory.
+func NewSender(cfg *viper.Viper, log *logger.Logger) Sender {
+	switch cfg.GetString("smtp.sender") {
+	case "", "smtp":
+		return newSMTPSender(cfg)
+	case "file":
+		return newFileSender(cfg)
+	case "log":
+		return newLogSender(log)
+	case "memory":
+		return NewMemorySender()
+	default:
+		panic("smtp sender can be 'smtp', 'file', 'log' or 'memory'")
+	}
+}
+
 type SendInput struct {
-	To            []string
-	Subject       string
-	Body          string
+	To      []string
+	Subject string
+	Body    string
+	// TextBody is sent as plain text alternative of the HTML body, if it's set.
+	TextBody      string
+	Headers       map[string][]string
 	EmbeddedFiles []EmbeddedFile
 	ContentType   ContentType
 }
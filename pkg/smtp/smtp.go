@@ -8,10 +8,28 @@ import (
 
 type smtpSender struct {
 	*gomail.Dialer
-	from string
+	from fromAddress
 }
 
-func NewSMTPSender(cfg *viper.Viper) Sender {
+// fromAddress is the sender of emails, smtp.from defaults to smtp.username and smtp.from_name is its display name.
+type fromAddress struct {
+	address string
+	name    string
+}
+
+func newFromAddress(cfg *viper.Viper) fromAddress {
+	address := cfg.GetString("smtp.from")
+	if address == "" {
+		address = cfg.GetString("smtp.username")
+	}
+
+	return fromAddress{
+		address: address,
+		name:    cfg.GetString("smtp.from_name"),
+	}
+}
+
+func newSMTPSender(cfg *viper.Viper) Sender {
 	dialer := gomail.NewDialer(
 		cfg.GetString("smtp.host"),
 		cfg.GetInt("smtp.port"),
@@ -21,17 +39,36 @@ func NewSMTPSender(cfg *viper.Viper) Sender {
 
 	return &smtpSender{
 		Dialer: dialer,
-		from:   cfg.GetString("smtp.username"),
+		from:   newFromAddress(cfg),
 	}
 }
 
 func (s *smtpSender) Send(input SendInput) error {
+	if err := s.DialAndSend(newMessage(s.from, input)); err != nil {
+		return errors.Wrap(err, "error sending email via smtp")
+	}
+
+	return nil
+}
+
+func newMessage(from fromAddress, input SendInput) *gomail.Message {
 	msg := gomail.NewMessage()
 
-	msg.SetHeader("From", s.from)
+	msg.SetAddressHeader("From", from.address, from.name)
 	msg.SetHeader("To", input.To...)
 	msg.SetHeader("Subject", input.Subject)
-	msg.SetBody(string(input.ContentType), input.Body)
+
+	for name, values := range input.Headers {
+		msg.SetHeader(name, values...)
+	}
+
+	// clients show the last alternative they support, so the plain text goes first
+	if input.TextBody != "" && input.ContentType == HTMLContentType {
+		msg.SetBody(string(TextContentType), input.TextBody)
+		msg.AddAlternative(string(HTMLContentType), input.Body)
+	} else {
+		msg.SetBody(string(input.ContentType), input.Body)
+	}
 
 	if input.EmbeddedFiles != nil {
 		for _, embeddedFile := range input.EmbeddedFiles {
@@ -39,9 +76,5 @@ func (s *smtpSender) Send(input SendInput) error {
 		}
 	}
 
-	if err := s.DialAndSend(msg); err != nil {
-		return errors.Wrap(err, "error sending email via smtp")
-	}
-
-	return nil
+	return msg
 }